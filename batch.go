@@ -0,0 +1,277 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// batchWriteSize is the max number of items DynamoDb accepts in a single BatchWriteItem request.
+const batchWriteSize = 25
+
+// batchGetSize is the max number of keys DynamoDb accepts in a single BatchGetItem request.
+const batchGetSize = 100
+
+// batchMaxRetries defines how often unprocessed items of a batch request will be retried.
+const batchMaxRetries = 5
+
+// batchRetryDelay is the delay before the first retry of unprocessed items. It's doubled for each further retry.
+const batchRetryDelay = 50 * time.Millisecond
+
+// AddAll calls AddAllWithContext with context.Background().
+func (r *DynamoDbRepository) AddAll(items []ItemKey) error {
+	return r.AddAllWithContext(context.Background(), items)
+}
+
+// AddAllWithContext will create or update all passed items using batch requests.
+// If passed items contain the same key multiple times, only the last item is written.
+// Versioned items are not supported, because batch writes can't check their version. They're reported
+// together with all other items which couldn't be written by a BatchError.
+func (r *DynamoDbRepository) AddAllWithContext(ctx context.Context, items []ItemKey) error {
+
+	r.logger.Debugf("Add %d items", len(items))
+
+	failures := []BatchItemError{}
+	requests := []*dynamodb.WriteRequest{}
+	// DynamoDb rejects batch requests with duplicate keys.
+	requestIndex := make(map[string]int)
+	for _, item := range items {
+
+		if item.GetObjectType() == lockObjectType {
+			failures = append(failures, BatchItemError{Key: item, Err: fmt.Errorf("Unsupported object type for Add: %s", item.GetObjectType())})
+			continue
+		}
+		if _, ok := item.(Versioned); ok {
+			failures = append(failures, BatchItemError{Key: item, Err: fmt.Errorf("Unsupported versioned item for AddAll: %s", identifierAsString(item))})
+			continue
+		}
+
+		av, err := dynamodbattribute.MarshalMap(item)
+		if err != nil {
+			failures = append(failures, BatchItemError{Key: item, Err: err})
+			continue
+		}
+		request := &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: av}}
+		if idx, ok := requestIndex[identifierAsString(item)]; ok {
+			requests[idx] = request
+			continue
+		}
+		requestIndex[identifierAsString(item)] = len(requests)
+		requests = append(requests, request)
+	}
+	return newBatchError(append(failures, r.batchWrite(ctx, requests)...))
+}
+
+// DeleteAll calls DeleteAllWithContext with context.Background().
+func (r *DynamoDbRepository) DeleteAll(items []ItemKey) error {
+	return r.DeleteAllWithContext(context.Background(), items)
+}
+
+// DeleteAllWithContext will delete all items identified by passed keys using batch requests.
+// Duplicate keys are deleted only once. Items which couldn't be deleted are reported by a BatchError.
+func (r *DynamoDbRepository) DeleteAllWithContext(ctx context.Context, items []ItemKey) error {
+
+	r.logger.Debugf("Delete %d items", len(items))
+
+	requests := []*dynamodb.WriteRequest{}
+	// DynamoDb rejects batch requests with duplicate keys.
+	requestedKeys := make(map[string]bool)
+	for _, item := range items {
+		if requestedKeys[identifierAsString(item)] {
+			continue
+		}
+		requestedKeys[identifierAsString(item)] = true
+		requests = append(requests, &dynamodb.WriteRequest{DeleteRequest: &dynamodb.DeleteRequest{Key: r.newItemKey(item)}})
+	}
+	return newBatchError(r.batchWrite(ctx, requests))
+}
+
+// batchWrite splits passed write requests into chunks of max batch size and sends them to DynamoDb.
+// Unprocessed items will be retried with an exponential backoff. Returns an error for each failed write request.
+func (r *DynamoDbRepository) batchWrite(ctx context.Context, requests []*dynamodb.WriteRequest) []BatchItemError {
+
+	failures := []BatchItemError{}
+	for start := 0; start < len(requests); start += batchWriteSize {
+
+		end := start + batchWriteSize
+		if end > len(requests) {
+			end = len(requests)
+		}
+		failures = append(failures, r.batchWriteChunk(ctx, requests[start:end])...)
+	}
+	return failures
+}
+
+// batchWriteChunk sends passed write requests in a single batch and retries unprocessed items.
+func (r *DynamoDbRepository) batchWriteChunk(ctx context.Context, requests []*dynamodb.WriteRequest) []BatchItemError {
+
+	for attempt := 0; len(requests) > 0; attempt++ {
+
+		input := &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]*dynamodb.WriteRequest{aws.StringValue(r.tableName): requests},
+		}
+		result, err := r.dynamoDb().BatchWriteItemWithContext(ctx, input)
+		if err != nil {
+			return writeRequestFailures(requests, err)
+		}
+
+		requests = result.UnprocessedItems[aws.StringValue(r.tableName)]
+		if len(requests) == 0 {
+			return []BatchItemError{}
+		}
+		if attempt >= batchMaxRetries {
+			return writeRequestFailures(requests, nil)
+		}
+
+		r.logger.Debugf("Retry %d unprocessed items, attempt: %d", len(requests), attempt+1)
+		if err := sleepWithContext(ctx, backoffDelay(attempt, batchRetryDelay)); err != nil {
+			return writeRequestFailures(requests, err)
+		}
+	}
+	return []BatchItemError{}
+}
+
+// writeRequestFailures returns an error for each passed write request. Passed error is wrapped
+// by sentinel errors if possible. If it's nil, requests are reported as throttled.
+func writeRequestFailures(requests []*dynamodb.WriteRequest, err error) []BatchItemError {
+
+	keys := []map[string]*dynamodb.AttributeValue{}
+	for _, request := range requests {
+		if request.PutRequest != nil {
+			keys = append(keys, request.PutRequest.Item)
+		} else {
+			keys = append(keys, request.DeleteRequest.Key)
+		}
+	}
+	return keyFailures(keys, err)
+}
+
+// GetAll calls GetAllWithContext with context.Background().
+func (r *DynamoDbRepository) GetAll(keys []ItemKey, receiver interface{}, opts ...ReadOption) ([]ItemKey, error) {
+	return r.GetAllWithContext(context.Background(), keys, receiver, opts...)
+}
+
+// GetAllWithContext reads all items for passed keys using batch requests. Receiver have to be a pointer to a slice
+// of expected type, found items are unmarshalled into it. Order of items in receiver is not guaranteed.
+// Returns all keys no item exists for. Keys which couldn't be read are reported by a BatchError.
+// Use WithConsistentRead to get strongly consistent reads.
+func (r *DynamoDbRepository) GetAllWithContext(ctx context.Context, keys []ItemKey, receiver interface{}, opts ...ReadOption) ([]ItemKey, error) {
+
+	r.logger.Debugf("Get %d items", len(keys))
+
+	if reflect.ValueOf(receiver).Kind() != reflect.Ptr {
+		msg := "Expect pointer receiver for items."
+		r.logger.Error(msg)
+		return nil, errors.New(msg)
+	}
+
+	// DynamoDb rejects batch requests with duplicate keys.
+	uniqueKeys := []ItemKey{}
+	requestedKeys := make(map[string]bool)
+	for _, key := range keys {
+		if !requestedKeys[identifierAsString(key)] {
+			requestedKeys[identifierAsString(key)] = true
+			uniqueKeys = append(uniqueKeys, key)
+		}
+	}
+
+	options := newReadOptions(opts)
+	items := []map[string]*dynamodb.AttributeValue{}
+	failures := []BatchItemError{}
+	for start := 0; start < len(uniqueKeys); start += batchGetSize {
+
+		end := start + batchGetSize
+		if end > len(uniqueKeys) {
+			end = len(uniqueKeys)
+		}
+		chunkItems, chunkFailures := r.batchGetChunk(ctx, uniqueKeys[start:end], options)
+		items = append(items, chunkItems...)
+		failures = append(failures, chunkFailures...)
+	}
+
+	foundKeys := make(map[string]bool)
+	for _, item := range items {
+		foundKeys[identifierAsString(itemKeyFromAttributes(item))] = true
+	}
+	for _, failure := range failures {
+		foundKeys[identifierAsString(failure.Key)] = true
+	}
+	notFound := []ItemKey{}
+	for _, key := range uniqueKeys {
+		if !foundKeys[identifierAsString(key)] {
+			notFound = append(notFound, key)
+		}
+	}
+
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, receiver); err != nil {
+		return notFound, err
+	}
+	return notFound, newBatchError(failures)
+}
+
+// batchGetChunk reads items for passed keys in a single batch and retries unprocessed keys.
+func (r *DynamoDbRepository) batchGetChunk(ctx context.Context, keys []ItemKey, options *readOptions) ([]map[string]*dynamodb.AttributeValue, []BatchItemError) {
+
+	requestKeys := []map[string]*dynamodb.AttributeValue{}
+	for _, key := range keys {
+		requestKeys = append(requestKeys, r.newItemKey(key))
+	}
+
+	items := []map[string]*dynamodb.AttributeValue{}
+	for attempt := 0; len(requestKeys) > 0; attempt++ {
+
+		input := &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]*dynamodb.KeysAndAttributes{
+				aws.StringValue(r.tableName): &dynamodb.KeysAndAttributes{
+					Keys:           requestKeys,
+					ConsistentRead: aws.Bool(options.consistentRead),
+				},
+			},
+		}
+		result, err := r.dynamoDb().BatchGetItemWithContext(ctx, input)
+		if err != nil {
+			return items, keyFailures(requestKeys, err)
+		}
+
+		items = append(items, result.Responses[aws.StringValue(r.tableName)]...)
+		requestKeys = nil
+		if unprocessed, ok := result.UnprocessedKeys[aws.StringValue(r.tableName)]; ok {
+			requestKeys = unprocessed.Keys
+		}
+		if len(requestKeys) == 0 {
+			break
+		}
+		if attempt >= batchMaxRetries {
+			return items, keyFailures(requestKeys, nil)
+		}
+
+		r.logger.Debugf("Retry %d unprocessed keys, attempt: %d", len(requestKeys), attempt+1)
+		if err := sleepWithContext(ctx, backoffDelay(attempt, batchRetryDelay)); err != nil {
+			return items, keyFailures(requestKeys, err)
+		}
+	}
+	return items, []BatchItemError{}
+}
+
+// keyFailures returns an error for each passed key. Passed error is wrapped
+// by sentinel errors if possible. If it's nil, keys are reported as throttled.
+func keyFailures(keys []map[string]*dynamodb.AttributeValue, err error) []BatchItemError {
+
+	failures := []BatchItemError{}
+	for _, attributes := range keys {
+
+		key := itemKeyFromAttributes(attributes)
+		if err == nil {
+			failures = append(failures, BatchItemError{Key: key, Err: newRepositoryError(ErrThrottled, key, nil)})
+		} else {
+			failures = append(failures, BatchItemError{Key: key, Err: wrapError(err, key, ErrConditionFailed)})
+		}
+	}
+	return failures
+}
@@ -0,0 +1,93 @@
+package dynamodb
+
+import (
+	"errors"
+)
+
+func (suite *RepositoryTestSuite) TestBatchWrite() {
+
+	itemCount := 60
+	items := []ItemKey{}
+	for i := 1; i <= itemCount; i++ {
+		items = append(items, newItemForTest())
+	}
+	suite.Nil(suite.repo.AddAll(items))
+
+	result := []testItem{}
+	suite.Nil(suite.repo.Query("TestItems", &result))
+	suite.Len(result, itemCount)
+
+	suite.Nil(suite.repo.DeleteAll(items))
+
+	result2 := []testItem{}
+	suite.Nil(suite.repo.Query("TestItems", &result2))
+	suite.Len(result2, 0)
+}
+
+func (suite *RepositoryTestSuite) TestBatchWriteWithDuplicateKeys() {
+
+	item := newItemForTest()
+	item2 := newTestItemWithoutValues(item)
+	item2.Val1 = "yYy"
+	items := []ItemKey{item, newItemForTest(), item2}
+	suite.Nil(suite.repo.AddAll(items))
+
+	result := []testItem{}
+	suite.Nil(suite.repo.Query("TestItems", &result))
+	suite.Len(result, 2)
+
+	item3 := newTestItemWithoutValues(item)
+	suite.Nil(suite.repo.Get(item3))
+	suite.Equal("yYy", item3.Val1)
+
+	suite.Nil(suite.repo.DeleteAll(append(items, item)))
+
+	result2 := []testItem{}
+	suite.Nil(suite.repo.Query("TestItems", &result2))
+	suite.Len(result2, 0)
+}
+
+func (suite *RepositoryTestSuite) TestBatchWriteWithErrors() {
+
+	itemLock := &ItemLock{ItemIdentifier: NewItemIdentifier("id-1", lockObjectType)}
+	items := []ItemKey{newItemForTest(), itemLock, newItemForTest()}
+	err := suite.repo.AddAll(items)
+	suite.NotNil(err)
+
+	var batchErr *BatchError
+	suite.True(errors.As(err, &batchErr))
+	suite.Len(batchErr.Failures, 1)
+	suite.Equal(itemLock, batchErr.Failures[0].Key)
+
+	result := []testItem{}
+	suite.Nil(suite.repo.Query("TestItems", &result))
+	suite.Len(result, 2)
+
+	suite.repo.(*DynamoDbRepository).tableName = nil
+	err2 := suite.repo.DeleteAll([]ItemKey{items[0], items[2]})
+	suite.True(errors.As(err2, &batchErr))
+	suite.Len(batchErr.Failures, 2)
+}
+
+func (suite *RepositoryTestSuite) TestBatchGet() {
+
+	itemCount := 120
+	keys := []ItemKey{}
+	for i := 1; i <= itemCount; i++ {
+		item := newItemForTest()
+		suite.Nil(suite.repo.Add(item))
+		keys = append(keys, item)
+	}
+	missingKey := NewItemIdentifier("xxx", "TestItems")
+	keys = append(keys, missingKey, keys[0])
+
+	items := []testItem{}
+	notFound, err := suite.repo.GetAll(keys, &items)
+	suite.Nil(err)
+	suite.Len(items, itemCount)
+	suite.Len(notFound, 1)
+	suite.Equal(missingKey, notFound[0])
+
+	_, err2 := suite.repo.GetAll(keys, []testItem{})
+	suite.NotNil(err2)
+}
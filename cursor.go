@@ -0,0 +1,136 @@
+package dynamodb
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// cursorPayload is the content of a cursor, used to continue a query at a specific item.
+type cursorPayload struct {
+
+	// ObjectType a cursor has been created for.
+	ObjectType string `json:"t"`
+
+	// LastEvaluatedKey is the key of the last item of a page.
+	LastEvaluatedKey map[string]*dynamodb.AttributeValue `json:"k"`
+}
+
+// QueryPage calls QueryPageWithContext with context.Background().
+func (r *DynamoDbRepository) QueryPage(objectType string, limit int64, cursor string, receiver interface{}, opts ...ReadOption) (string, error) {
+	return r.QueryPageWithContext(context.Background(), objectType, limit, cursor, receiver, opts...)
+}
+
+// QueryPageWithContext reads a single page of items for passed object type. Limit defines the max number of items on a page,
+// passed cursor defines where the page starts. Use an empty cursor to get the first page.
+// Returns a cursor for the next page, which is empty if there're no further items.
+// Cursors are signed, a manipulated cursor or a cursor for another object type is rejected by ErrInvalidCursor.
+// If read options contain a filter, a page can contain less items than passed limit.
+// A secret for cursors has to be set by config key aws.dynamodb.cursor.secret, otherwise an error is returned.
+func (r *DynamoDbRepository) QueryPageWithContext(ctx context.Context, objectType string, limit int64, cursor string, receiver interface{}, opts ...ReadOption) (string, error) {
+
+	r.logger.Debugf("Query page of %s items with limit %d", objectType, limit)
+
+	if reflect.ValueOf(receiver).Kind() != reflect.Ptr {
+		msg := "Expect pointer receiver for items."
+		r.logger.Error(msg)
+		return "", errors.New(msg)
+	}
+
+	if len(r.cursorSecret) == 0 {
+		msg := "Missing secret for cursors, set it by config key aws.dynamodb.cursor.secret."
+		r.logger.Error(msg)
+		return "", errors.New(msg)
+	}
+
+	input, err := r.newQueryInput(objectType, newReadOptions(opts))
+	if err != nil {
+		return "", err
+	}
+	input.Limit = aws.Int64(limit)
+
+	if cursor != "" {
+		startKey, err := r.decodeCursor(objectType, cursor)
+		if err != nil {
+			return "", err
+		}
+		input.ExclusiveStartKey = startKey
+	}
+
+	result, err := r.dynamoDb().QueryWithContext(ctx, input)
+	if err != nil {
+		return "", wrapError(err, NewItemIdentifier("", objectType), ErrConditionFailed)
+	}
+	r.logger.Debugf("Query Result: %+v", result)
+
+	if err := dynamodbattribute.UnmarshalListOfMaps(result.Items, receiver); err != nil {
+		return "", err
+	}
+	if len(result.LastEvaluatedKey) == 0 {
+		return "", nil
+	}
+	return r.encodeCursor(objectType, result.LastEvaluatedKey)
+}
+
+// encodeCursor returns a signed cursor for passed object type and last evaluated key.
+func (r *DynamoDbRepository) encodeCursor(objectType string, lastEvaluatedKey map[string]*dynamodb.AttributeValue) (string, error) {
+
+	payload, err := json.Marshal(cursorPayload{ObjectType: objectType, LastEvaluatedKey: lastEvaluatedKey})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(r.signCursor(payload)), nil
+}
+
+// decodeCursor verifies the signature of passed cursor and returns its last evaluated key.
+// Returns ErrInvalidCursor if a cursor is malformed, has been manipulated or belongs to another object type.
+func (r *DynamoDbRepository) decodeCursor(objectType, cursor string) (map[string]*dynamodb.AttributeValue, error) {
+
+	invalidCursor := newRepositoryError(ErrInvalidCursor, NewItemIdentifier("", objectType), nil)
+
+	parts := strings.Split(cursor, ".")
+	if len(parts) != 2 {
+		return nil, invalidCursor
+	}
+	payload, err1 := base64.RawURLEncoding.DecodeString(parts[0])
+	signature, err2 := base64.RawURLEncoding.DecodeString(parts[1])
+	if err1 != nil || err2 != nil || !hmac.Equal(signature, r.signCursor(payload)) {
+		return nil, invalidCursor
+	}
+
+	decodedCursor := cursorPayload{}
+	if err := json.Unmarshal(payload, &decodedCursor); err != nil || decodedCursor.ObjectType != objectType {
+		return nil, invalidCursor
+	}
+	if attr, ok := decodedCursor.LastEvaluatedKey["ObjectType"]; !ok || aws.StringValue(attr.S) != objectType {
+		return nil, invalidCursor
+	}
+	return decodedCursor.LastEvaluatedKey, nil
+}
+
+// signCursor returns a HMAC signature for passed cursor payload.
+func (r *DynamoDbRepository) signCursor(payload []byte) []byte {
+
+	mac := hmac.New(sha256.New, r.cursorSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// newCursorSecret returns passed secret from config. Returns nil if there's no secret.
+func newCursorSecret(secret *string) []byte {
+
+	if secret != nil && *secret != "" {
+		return []byte(*secret)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package dynamodb
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/suite"
+	config "github.com/tommzn/go-config"
+	log "github.com/tommzn/go-log"
+)
+
+type CursorTestSuite struct {
+	suite.Suite
+}
+
+func TestCursorTestSuite(t *testing.T) {
+	suite.Run(t, new(CursorTestSuite))
+}
+
+func (suite *CursorTestSuite) TestEncodeAndDecodeCursor() {
+
+	repo := newRepositoryForTest()
+	lastEvaluatedKey := map[string]*dynamodb.AttributeValue{
+		"ObjectType": &dynamodb.AttributeValue{S: aws.String("TestItems")},
+		"Id":         &dynamodb.AttributeValue{S: aws.String("id-1")},
+	}
+	cursor, err := repo.encodeCursor("TestItems", lastEvaluatedKey)
+	suite.Nil(err)
+
+	decodedKey, err := repo.decodeCursor("TestItems", cursor)
+	suite.Nil(err)
+	suite.Equal(lastEvaluatedKey, decodedKey)
+
+	_, err = repo.decodeCursor("OtherItems", cursor)
+	suite.True(errors.Is(err, ErrInvalidCursor))
+
+	parts := strings.Split(cursor, ".")
+	_, err = repo.decodeCursor("TestItems", parts[0])
+	suite.True(errors.Is(err, ErrInvalidCursor))
+	_, err = repo.decodeCursor("TestItems", parts[0]+"x."+parts[1])
+	suite.True(errors.Is(err, ErrInvalidCursor))
+
+	otherRepo := newRepositoryForTest()
+	_, err = otherRepo.decodeCursor("TestItems", cursor)
+	suite.Nil(err)
+	otherRepo.cursorSecret = []byte("other-secret")
+	_, err = otherRepo.decodeCursor("TestItems", cursor)
+	suite.True(errors.Is(err, ErrInvalidCursor))
+
+	forgedKey := map[string]*dynamodb.AttributeValue{
+		"ObjectType": &dynamodb.AttributeValue{S: aws.String("OtherItems")},
+		"Id":         &dynamodb.AttributeValue{S: aws.String("id-1")},
+	}
+	forgedCursor, _ := repo.encodeCursor("TestItems", forgedKey)
+	_, err = repo.decodeCursor("TestItems", forgedCursor)
+	suite.True(errors.Is(err, ErrInvalidCursor))
+}
+
+func (suite *CursorTestSuite) TestCursorSecret() {
+
+	suite.Equal([]byte("secret"), newCursorSecret(aws.String("secret")))
+	suite.Nil(newCursorSecret(nil))
+	suite.Nil(newCursorSecret(aws.String("")))
+
+	suite.NotNil(newRepositoryForTest().cursorSecret)
+
+	conf, err := config.NewStaticConfigSource("aws:\n  dynamodb:\n    tablename: DynamoDbTest\n").Load()
+	suite.Nil(err)
+	repo := NewRepository(conf, loggerForTest(log.Error))
+	_, err = repo.QueryPage("TestItems", 3, "", &[]testItem{})
+	suite.NotNil(err)
+}
+
+func (suite *RepositoryTestSuite) TestQueryPages() {
+
+	itemCount := 7
+	for i := 1; i <= itemCount; i++ {
+		suite.Nil(suite.repo.Add(newItemForTest()))
+	}
+
+	count := 0
+	pages := 0
+	cursor := ""
+	for {
+		items := []testItem{}
+		nextCursor, err := suite.repo.QueryPage("TestItems", 3, cursor, &items)
+		suite.Nil(err)
+		suite.True(len(items) <= 3)
+		count += len(items)
+		pages++
+		if nextCursor == "" || pages > itemCount {
+			break
+		}
+		cursor = nextCursor
+	}
+	suite.Equal(itemCount, count)
+	suite.Equal(3, pages)
+
+	items := []testItem{}
+	cursor, err := suite.repo.QueryPage("TestItems", 3, "", &items)
+	suite.Nil(err)
+	_, err = suite.repo.QueryPage("OtherItems", 3, cursor, &items)
+	suite.True(errors.Is(err, ErrInvalidCursor))
+
+	_, err = suite.repo.QueryPage("TestItems", 3, "", items)
+	suite.NotNil(err)
+}
@@ -2,8 +2,6 @@
 package dynamodb
 
 import (
-	"time"
-
 	"github.com/aws/aws-sdk-go/aws"
 	config "github.com/tommzn/go-config"
 	log "github.com/tommzn/go-log"
@@ -11,6 +9,9 @@ import (
 
 // NewRepository creates a new DynamoDb repository by passed coinfig.
 // By config you can defins the table name, region and endpoint for a local dynamodb.
+// Default life time of locks can be set by aws.dynamodb.locks.ttl, e.g. 30s or 10m. Invalid or non-positive values are ignored.
+// Use aws.dynamodb.cursor.secret to define a secret for signing cursors of paginated queries. It's required
+// by QueryPage and has to be the same for all repositories which should accept each others cursors.
 func NewRepository(conf config.Config, logger log.Logger) Repository {
 
 	tableName := conf.Get("aws.dynamodb.tablename", config.AsStringPtr(DEFAULT_TABLENAME))
@@ -18,10 +19,15 @@ func NewRepository(conf config.Config, logger log.Logger) Repository {
 		Region:   conf.Get("aws.dynamodb.region", config.AsStringPtr(DEFAULT_AWS_REGION)),
 		Endpoint: conf.Get("aws.dynamodb.endpoint", nil),
 	}
+	lockTtl := DEFAULT_LOCK_TTL
+	if ttl := conf.GetAsDuration("aws.dynamodb.locks.ttl", nil); ttl != nil && *ttl > 0 {
+		lockTtl = *ttl
+	}
 	return &DynamoDbRepository{
-		config:    awsConfig,
-		tableName: tableName,
-		logger:    logger,
-		lockTtl:   5 * time.Minute,
+		config:       awsConfig,
+		tableName:    tableName,
+		logger:       logger,
+		lockTtl:      lockTtl,
+		cursorSecret: newCursorSecret(conf.Get("aws.dynamodb.cursor.secret", nil)),
 	}
 }
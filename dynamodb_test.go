@@ -0,0 +1,54 @@
+package dynamodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	config "github.com/tommzn/go-config"
+	log "github.com/tommzn/go-log"
+)
+
+type DynamoDbTestSuite struct {
+	suite.Suite
+}
+
+func TestDynamoDbTestSuite(t *testing.T) {
+	suite.Run(t, new(DynamoDbTestSuite))
+}
+
+func (suite *DynamoDbTestSuite) TestNewRepositoryWithLockTtl() {
+
+	repo := newRepositoryForTest()
+	suite.Equal(DEFAULT_LOCK_TTL, repo.lockTtl)
+
+	conf, err := config.NewStaticConfigSource("aws:\n  dynamodb:\n    locks:\n      ttl: 30s\n").Load()
+	suite.Nil(err)
+	repo2 := NewRepository(conf, loggerForTest(log.Error)).(*DynamoDbRepository)
+	suite.Equal(30*time.Second, repo2.lockTtl)
+
+	conf3, err := config.NewStaticConfigSource("aws:\n  dynamodb:\n    locks:\n      ttl: -30s\n").Load()
+	suite.Nil(err)
+	repo3 := NewRepository(conf3, loggerForTest(log.Error)).(*DynamoDbRepository)
+	suite.Equal(DEFAULT_LOCK_TTL, repo3.lockTtl)
+
+	conf4, err := config.NewStaticConfigSource("aws:\n  dynamodb:\n    locks:\n      ttl: 0s\n").Load()
+	suite.Nil(err)
+	repo4 := NewRepository(conf4, loggerForTest(log.Error)).(*DynamoDbRepository)
+	suite.Equal(DEFAULT_LOCK_TTL, repo4.lockTtl)
+}
+
+func (suite *DynamoDbTestSuite) TestLockWithInvalidTtl() {
+
+	repo := newRepositoryForTest()
+	item := newItemForTest()
+
+	_, err := repo.LockWithTTL(item, 0)
+	suite.NotNil(err)
+	_, err1 := repo.LockWithTTL(item, -1*time.Minute)
+	suite.NotNil(err1)
+
+	itemLock := &ItemLock{ItemIdentifier: NewItemIdentifier(identifierAsString(item), lockObjectType)}
+	_, err2 := repo.RenewWithTTL(itemLock, 0)
+	suite.NotNil(err2)
+}
@@ -0,0 +1,161 @@
+package dynamodb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ErrNotFound is returned if a requested item doesn't exist in DynamoDb.
+var ErrNotFound = errors.New("Not found")
+
+// ErrAlreadyExists is returned if an item should be created, but an item with same key already exists.
+var ErrAlreadyExists = errors.New("Already exists")
+
+// ErrVersionConflict is returned if an item has been modified by someone else since it has been read.
+var ErrVersionConflict = errors.New("Version conflict")
+
+// ErrLockHeld is returned if a lock can't be obtained, because it's held by someone else.
+var ErrLockHeld = errors.New("Lock held")
+
+// ErrLockLost is returned if a lock has expired or has been taken over by someone else.
+var ErrLockLost = errors.New("Lock lost")
+
+// ErrConditionFailed is returned if a condition of a write request is not fulfilled.
+var ErrConditionFailed = errors.New("Condition failed")
+
+// ErrTransactionConflict is returned if a transaction has been canceled because of a concurrent request for an item.
+var ErrTransactionConflict = errors.New("Transaction conflict")
+
+// ErrInvalidCursor is returned if a cursor for a paginated query is malformed or has been manipulated.
+var ErrInvalidCursor = errors.New("Invalid cursor")
+
+// ErrThrottled is returned if DynamoDb rejects a request because of exceeded throughput or request limits.
+var ErrThrottled = errors.New("Throttled")
+
+// throttlingErrorCodes is a list of AWS error codes DynamoDb returns for throttled requests.
+var throttlingErrorCodes = map[string]bool{
+	dynamodb.ErrCodeProvisionedThroughputExceededException: true,
+	dynamodb.ErrCodeRequestLimitExceeded:                   true,
+	"ThrottlingException":                                  true,
+}
+
+// RepositoryError is returned for errors which can be assigned to a sentinel error, e.g. ErrNotFound.
+// Use errors.Is to check for a sentinel error and errors.As to get the underlying AWS error.
+type RepositoryError struct {
+
+	// Kind is the sentinel error, e.g. ErrNotFound or ErrLockHeld.
+	Kind error
+
+	// Key is the string representation of the item key an error occurred for.
+	Key string
+
+	// Err is the underlying error returned by DynamoDb. Can be nil.
+	Err error
+}
+
+// Error returns a message including sentinel error, item key and underlying error.
+func (e *RepositoryError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%s: %s", e.Kind, e.Key)
+	}
+	return fmt.Sprintf("%s: %s, %s", e.Kind, e.Key, e.Err)
+}
+
+// Is returns true if passed target is the sentinel error of this error.
+func (e *RepositoryError) Is(target error) bool {
+	return e.Kind == target
+}
+
+// Unwrap returns the underlying error returned by DynamoDb.
+func (e *RepositoryError) Unwrap() error {
+	return e.Err
+}
+
+// newRepositoryError returns a new error of passed kind for given item key.
+func newRepositoryError(kind error, key ItemKey, err error) error {
+	return &RepositoryError{Kind: kind, Key: identifierAsString(key), Err: err}
+}
+
+// wrapError assigns passed error to a sentinel error if possible. A failed condition is reported
+// as passed conditionFailed kind, this allows to distinguish e.g. a held lock from other conditions.
+// All other errors are returned without any changes.
+func wrapError(err error, key ItemKey, conditionFailed error) error {
+
+	var awsErr awserr.Error
+	if err == nil || !errors.As(err, &awsErr) {
+		return err
+	}
+
+	if awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return newRepositoryError(conditionFailed, key, err)
+	}
+	if throttlingErrorCodes[awsErr.Code()] {
+		return newRepositoryError(ErrThrottled, key, err)
+	}
+	return err
+}
+
+// BatchError is returned by batch actions if at least one item couldn't be processed.
+// All other items of a batch have been processed successfully.
+type BatchError struct {
+
+	// Failures contains an error for each item which couldn't be processed.
+	Failures []BatchItemError
+}
+
+// BatchItemError is an error for a single item of a batch action.
+type BatchItemError struct {
+
+	// Key of the item which couldn't be processed.
+	Key ItemKey
+
+	// Err is the reason why an item couldn't be processed.
+	Err error
+}
+
+// Error returns the number of failed items and the error of the first failed item.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("Batch failed for %d items, first error: %s", len(e.Failures), e.Failures[0].Err)
+}
+
+// newBatchError returns a BatchError for passed failures or nil if there're no failures.
+func newBatchError(failures []BatchItemError) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return &BatchError{Failures: failures}
+}
+
+// TransactionError is returned if DynamoDb has canceled a transaction.
+// Use errors.Is to check if any of the failed actions has a sentinel error, e.g. ErrConditionFailed.
+type TransactionError struct {
+
+	// Failures contains an error for each action which caused the cancellation.
+	Failures []BatchItemError
+
+	// Err is the underlying error returned by DynamoDb.
+	Err error
+}
+
+// Error returns the number of failed actions and the underlying error.
+func (e *TransactionError) Error() string {
+	return fmt.Sprintf("Transaction canceled, %d failed actions: %s", len(e.Failures), e.Err)
+}
+
+// Is returns true if passed target is the sentinel error of any failed action.
+func (e *TransactionError) Is(target error) bool {
+	for _, failure := range e.Failures {
+		if errors.Is(failure.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the underlying error returned by DynamoDb.
+func (e *TransactionError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,54 @@
+package dynamodb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/suite"
+)
+
+type ErrorsTestSuite struct {
+	suite.Suite
+}
+
+func TestErrorsTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrorsTestSuite))
+}
+
+func (suite *ErrorsTestSuite) TestWrapErrors() {
+
+	key := NewItemIdentifier("id-1", "TestItem")
+	suite.Nil(wrapError(nil, key, ErrConditionFailed))
+
+	plainErr := errors.New("error")
+	suite.Equal(plainErr, wrapError(plainErr, key, ErrConditionFailed))
+
+	conditionErr := awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "condition failed", nil)
+	err1 := wrapError(conditionErr, key, ErrConditionFailed)
+	suite.True(errors.Is(err1, ErrConditionFailed))
+	suite.False(errors.Is(err1, ErrLockHeld))
+	suite.Contains(err1.Error(), "TestItem:id-1")
+
+	var awsErr awserr.Error
+	suite.True(errors.As(err1, &awsErr))
+	suite.Equal(dynamodb.ErrCodeConditionalCheckFailedException, awsErr.Code())
+
+	err2 := wrapError(conditionErr, key, ErrLockHeld)
+	suite.True(errors.Is(err2, ErrLockHeld))
+
+	throttlingErr := awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "throttled", nil)
+	suite.True(errors.Is(wrapError(throttlingErr, key, ErrLockHeld), ErrThrottled))
+
+	otherErr := awserr.New(dynamodb.ErrCodeResourceNotFoundException, "no table", nil)
+	suite.Equal(otherErr, wrapError(otherErr, key, ErrConditionFailed))
+}
+
+func (suite *ErrorsTestSuite) TestNotFoundError() {
+
+	err := newRepositoryError(ErrNotFound, NewItemIdentifier("id-1", "TestItem"), nil)
+	suite.True(errors.Is(err, ErrNotFound))
+	suite.Nil(errors.Unwrap(err))
+	suite.Equal("Not found: TestItem:id-1", err.Error())
+}
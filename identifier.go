@@ -14,3 +14,13 @@ func (id *ItemIdentifier) GetId() string {
 func (id *ItemIdentifier) GetObjectType() string {
 	return id.ObjectType
 }
+
+// GetVersion returns the version of a DynamoDb item.
+func (v *ItemVersion) GetVersion() int64 {
+	return v.Version
+}
+
+// SetVersion assigns a new version to a DynamoDb item.
+func (v *ItemVersion) SetVersion(version int64) {
+	v.Version = version
+}
@@ -22,3 +22,11 @@ func (suite *IdentifierTestSuite) TestCreateItemKey() {
 	suite.Equal(id, itemKey.GetId())
 	suite.Equal(objectType, itemKey.GetObjectType())
 }
+
+func (suite *IdentifierTestSuite) TestItemVersion() {
+
+	version := &ItemVersion{}
+	suite.Equal(int64(0), version.GetVersion())
+	version.SetVersion(3)
+	suite.Equal(int64(3), version.GetVersion())
+}
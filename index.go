@@ -0,0 +1,33 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// QueryIndex calls QueryIndexWithContext with context.Background().
+func (r *DynamoDbRepository) QueryIndex(index SecondaryIndex, hashKeyValue interface{}, receiver interface{}, opts ...ReadOption) error {
+	return r.QueryIndexWithContext(context.Background(), index, hashKeyValue, receiver, opts...)
+}
+
+// QueryIndexWithContext will list all items of passed secondary index with given hash key value. Receiver have to be
+// a pointer to a slice of expected type and will be used to unmarshal query result. Query follows all
+// result pages. Results can be narrowed by read options, WithSortKey is applied to the range key of an index.
+// Global secondary indexes don't support WithConsistentRead.
+func (r *DynamoDbRepository) QueryIndexWithContext(ctx context.Context, index SecondaryIndex, hashKeyValue interface{}, receiver interface{}, opts ...ReadOption) error {
+
+	r.logger.Debugf("Query Index %s: %v", index.Name, hashKeyValue)
+
+	if reflect.ValueOf(receiver).Kind() != reflect.Ptr {
+		msg := "Expect pointer receiver for items."
+		r.logger.Error(msg)
+		return errors.New(msg)
+	}
+
+	input, err := r.newQueryInputForIndex(index, hashKeyValue, newReadOptions(opts))
+	if err != nil {
+		return err
+	}
+	return wrapError(r.queryAllPages(ctx, input, receiver), NewItemIdentifier("", index.Name), ErrConditionFailed)
+}
@@ -0,0 +1,65 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type IndexTestSuite struct {
+	suite.Suite
+}
+
+func TestIndexTestSuite(t *testing.T) {
+	suite.Run(t, new(IndexTestSuite))
+}
+
+func (suite *IndexTestSuite) TestNewQueryInputForIndex() {
+
+	repo := newRepositoryForTest()
+	index := SecondaryIndex{Name: "Val1Index", HashKey: "Val1", RangeKey: "Val2"}
+	input, err := repo.newQueryInputForIndex(index, "xXx", newReadOptions([]ReadOption{WithSortKey(SortKeyGreaterThan(5))}))
+	suite.Nil(err)
+	suite.Equal("Val1Index", *input.IndexName)
+	suite.Len(input.ExpressionAttributeNames, 2)
+	suite.Contains(*input.KeyConditionExpression, "AND")
+
+	input2, err2 := repo.newQueryInput("TestItems", newReadOptions([]ReadOption{}))
+	suite.Nil(err2)
+	suite.Nil(input2.IndexName)
+
+	indexWithoutRangeKey := SecondaryIndex{Name: "Val1Index", HashKey: "Val1"}
+	_, err3 := repo.newQueryInputForIndex(indexWithoutRangeKey, "xXx", newReadOptions([]ReadOption{}))
+	suite.Nil(err3)
+	_, err4 := repo.newQueryInputForIndex(indexWithoutRangeKey, "xXx", newReadOptions([]ReadOption{WithSortKey(SortKeyGreaterThan(5))}))
+	suite.NotNil(err4)
+}
+
+func (suite *RepositoryTestSuite) TestQueryIndex() {
+
+	index := SecondaryIndex{Name: "Val1Index", HashKey: "Val1", RangeKey: "Val2"}
+	suite.Nil(createIndexForTest(suite.conf, index))
+
+	for i := 1; i <= 5; i++ {
+		item := newItemForTest()
+		item.Val1 = "open"
+		item.Val2 = i
+		suite.Nil(suite.repo.Add(item))
+	}
+	suite.Nil(suite.repo.Add(newItemForTest()))
+
+	items := []testItem{}
+	suite.Nil(suite.repo.QueryIndex(index, "open", &items))
+	suite.Len(items, 5)
+
+	items2 := []testItem{}
+	suite.Nil(suite.repo.QueryIndex(index, "open", &items2, WithSortKey(SortKeyGreaterThan(3)), WithDescendingOrder()))
+	suite.Len(items2, 2)
+	suite.Equal(5, items2[0].Val2)
+
+	items3 := []testItem{}
+	suite.Nil(suite.repo.QueryIndex(index, "closed", &items3))
+	suite.Len(items3, 0)
+
+	suite.NotNil(suite.repo.QueryIndex(index, "open", items3))
+}
@@ -1,5 +1,10 @@
 package dynamodb
 
+import (
+	"context"
+	"time"
+)
+
 // ItemKey is an interface each object have to fulfill to be persisted
 // in DynamoDb.
 type ItemKey interface {
@@ -7,17 +12,56 @@ type ItemKey interface {
 	GetObjectType() string
 }
 
+// Versioned is an optional interface for items which should be protected against lost updates.
+// Add increments the version of such items and fails with ErrVersionConflict if the stored
+// item has been modified by someone else in the meantime. ItemVersion can be embedded to fulfill it.
+// A version has to be stored in attribute Version, as ItemVersion does, otherwise a versioned item is rejected.
+// Update and Put or Update of a transaction check and increment the version the same way, if passed
+// item or key is versioned. Increment always increments a stored version. AddAll doesn't support
+// versioned items, because batch writes can't be conditional, and reports them as failed.
+type Versioned interface {
+	GetVersion() int64
+	SetVersion(int64)
+}
+
 // Repository provides CRUD access for DynamoDb items.
 type Repository interface {
 
+	// ContextRepository provides all actions with an additional context to cancel or time-bound requests.
+	ContextRepository
+
 	// Add or update an item in DynamoDb.
 	Add(ItemKey) error
 
+	// AddIfNotExists will create a new item in DynamoDb, but never overwrites an existing item.
+	AddIfNotExists(ItemKey) error
+
 	// Get will try to read an item by specified key from DynamoDb.
-	Get(ItemKey) error
+	Get(ItemKey, ...ReadOption) error
 
 	// Query will list all items for an object type.
-	Query(string, interface{}) error
+	Query(string, interface{}, ...ReadOption) error
+
+	// QueryIter returns an iterator which reads items for an object type page by page.
+	QueryIter(string, ...ReadOption) *QueryIterator
+
+	// QueryPage reads a single page of items for an object type, starting at passed cursor.
+	QueryPage(string, int64, string, interface{}, ...ReadOption) (string, error)
+
+	// Count returns the number of items for an object type.
+	Count(string, ...ReadOption) (int64, error)
+
+	// QueryIndex will list all items of a secondary index for a hash key value.
+	QueryIndex(SecondaryIndex, interface{}, interface{}, ...ReadOption) error
+
+	// Scan reads all items of a table and calls passed handler for each item.
+	Scan(ScanHandler, ...ScanOption) error
+
+	// Update will apply all actions of passed spec to an item with specified key.
+	Update(ItemKey, *UpdateSpec) error
+
+	// Increment will add a delta to a number attribute of an item and returns the new value.
+	Increment(ItemKey, string, int64) (int64, error)
 
 	// Delete will remove an item with specified key from DynamoDb.
 	Delete(ItemKey) error
@@ -25,9 +69,104 @@ type Repository interface {
 	// Lock will try to obtain a lock for an items identified by passed key.
 	Lock(ItemKey) (*ItemLock, error)
 
+	// LockWithTTL will try to obtain a lock with passed life time for an items identified by passed key.
+	LockWithTTL(ItemKey, time.Duration) (*ItemLock, error)
+
 	// Renew can be used to extend lease of an item lock.
 	Renew(*ItemLock) (*ItemLock, error)
 
+	// RenewWithTTL can be used to extend lease of an item lock by passed duration.
+	RenewWithTTL(*ItemLock, time.Duration) (*ItemLock, error)
+
 	// Unlock will delete passed object lock from DynamoDb.
 	Unlock(*ItemLock) error
+
+	// AddAll will add or update all passed items using batch requests. Versioned items are not supported.
+	AddAll([]ItemKey) error
+
+	// DeleteAll will remove all items with passed keys using batch requests.
+	DeleteAll([]ItemKey) error
+
+	// GetAll will read all items for passed keys using batch requests and returns keys of missing items.
+	GetAll([]ItemKey, interface{}, ...ReadOption) ([]ItemKey, error)
+
+	// DeleteObjectType will remove all items of an object type using batch requests.
+	DeleteObjectType(string, ...PurgeOption) (int64, error)
+
+	// NewTransaction returns a new transaction to write multiple items atomically.
+	NewTransaction() *Transaction
+}
+
+// ContextRepository provides CRUD access for DynamoDb items. All methods expect a context
+// which is passed to AWS SDK calls and can be used to cancel or time-bound requests.
+type ContextRepository interface {
+
+	// AddWithContext will add or update an item in DynamoDb.
+	AddWithContext(context.Context, ItemKey) error
+
+	// AddIfNotExistsWithContext will create a new item in DynamoDb, but never overwrites an existing item.
+	AddIfNotExistsWithContext(context.Context, ItemKey) error
+
+	// GetWithContext will try to read an item by specified key from DynamoDb.
+	GetWithContext(context.Context, ItemKey, ...ReadOption) error
+
+	// QueryWithContext will list all items for an object type.
+	QueryWithContext(context.Context, string, interface{}, ...ReadOption) error
+
+	// QueryIterWithContext returns an iterator which reads items for an object type page by page.
+	QueryIterWithContext(context.Context, string, ...ReadOption) *QueryIterator
+
+	// QueryPageWithContext reads a single page of items for an object type, starting at passed cursor.
+	QueryPageWithContext(context.Context, string, int64, string, interface{}, ...ReadOption) (string, error)
+
+	// CountWithContext returns the number of items for an object type.
+	CountWithContext(context.Context, string, ...ReadOption) (int64, error)
+
+	// QueryIndexWithContext will list all items of a secondary index for a hash key value.
+	QueryIndexWithContext(context.Context, SecondaryIndex, interface{}, interface{}, ...ReadOption) error
+
+	// ScanWithContext reads all items of a table and calls passed handler for each item.
+	ScanWithContext(context.Context, ScanHandler, ...ScanOption) error
+
+	// UpdateWithContext will apply all actions of passed spec to an item with specified key.
+	UpdateWithContext(context.Context, ItemKey, *UpdateSpec) error
+
+	// IncrementWithContext will add a delta to a number attribute of an item and returns the new value.
+	IncrementWithContext(context.Context, ItemKey, string, int64) (int64, error)
+
+	// DeleteWithContext will remove an item with specified key from DynamoDb.
+	DeleteWithContext(context.Context, ItemKey) error
+
+	// LockWithContext will try to obtain a lock for an items identified by passed key.
+	LockWithContext(context.Context, ItemKey) (*ItemLock, error)
+
+	// LockWithTTLWithContext will try to obtain a lock with passed life time for an items identified by passed key.
+	LockWithTTLWithContext(context.Context, ItemKey, time.Duration) (*ItemLock, error)
+
+	// LockWait will retry to obtain a lock for an items identified by passed key until it succeeds or a max wait elapses.
+	LockWait(context.Context, ItemKey, ...LockOption) (*ItemLock, error)
+
+	// LockManaged will obtain a lock for an items identified by passed key, which is renewed in background until it's released.
+	LockManaged(context.Context, ItemKey, ...LockOption) (*ManagedLock, error)
+
+	// RenewWithContext can be used to extend lease of an item lock.
+	RenewWithContext(context.Context, *ItemLock) (*ItemLock, error)
+
+	// RenewWithTTLWithContext can be used to extend lease of an item lock by passed duration.
+	RenewWithTTLWithContext(context.Context, *ItemLock, time.Duration) (*ItemLock, error)
+
+	// UnlockWithContext will delete passed object lock from DynamoDb.
+	UnlockWithContext(context.Context, *ItemLock) error
+
+	// AddAllWithContext will add or update all passed items using batch requests. Versioned items are not supported.
+	AddAllWithContext(context.Context, []ItemKey) error
+
+	// DeleteAllWithContext will remove all items with passed keys using batch requests.
+	DeleteAllWithContext(context.Context, []ItemKey) error
+
+	// GetAllWithContext will read all items for passed keys using batch requests and returns keys of missing items.
+	GetAllWithContext(context.Context, []ItemKey, interface{}, ...ReadOption) ([]ItemKey, error)
+
+	// DeleteObjectTypeWithContext will remove all items of an object type using batch requests.
+	DeleteObjectTypeWithContext(context.Context, string, ...PurgeOption) (int64, error)
 }
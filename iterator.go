@@ -0,0 +1,130 @@
+package dynamodb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// QueryIterator reads items of a query page by page. A page is only requested from DynamoDb
+// if all items of the previous page have been consumed. Always call Close if you stop reading before
+// Next returns false, otherwise resources are released automatically. Check Err after Next returned false
+// to see if all items have been read.
+type QueryIterator struct {
+
+	// repository is used to request pages from DynamoDb.
+	repository *DynamoDbRepository
+
+	// objectType items are read for.
+	objectType string
+
+	// ctx is passed to all requests for a page.
+	ctx context.Context
+
+	// cancel will cancel the context of this iterator.
+	cancel context.CancelFunc
+
+	// input is the query input for the next page.
+	input *dynamodb.QueryInput
+
+	// items of the current page which haven't been consumed, yet.
+	items []map[string]*dynamodb.AttributeValue
+
+	// lastPage is true if there're no further pages.
+	lastPage bool
+
+	// closed is true if Close has been called.
+	closed bool
+
+	// err is the first error occurred while reading items.
+	err error
+}
+
+// QueryIter calls QueryIterWithContext with context.Background().
+func (r *DynamoDbRepository) QueryIter(objectType string, opts ...ReadOption) *QueryIterator {
+	return r.QueryIterWithContext(context.Background(), objectType, opts...)
+}
+
+// QueryIterWithContext returns an iterator for all items of passed object type. Results can be narrowed by read options.
+// Passed context is used for all page requests, iterating stops if it has been canceled.
+func (r *DynamoDbRepository) QueryIterWithContext(ctx context.Context, objectType string, opts ...ReadOption) *QueryIterator {
+
+	r.logger.Debug("Query Items: ", objectType)
+
+	iterCtx, cancel := context.WithCancel(ctx)
+	input, err := r.newQueryInput(objectType, newReadOptions(opts))
+	if err != nil {
+		cancel()
+	}
+	return &QueryIterator{
+		repository: r,
+		objectType: objectType,
+		ctx:        iterCtx,
+		cancel:     cancel,
+		input:      input,
+		items:      []map[string]*dynamodb.AttributeValue{},
+		lastPage:   err != nil,
+		err:        err,
+	}
+}
+
+// Next unmarshals the next item into passed receiver, which have to be a pointer.
+// Returns false if there're no further items, if the iterator has been closed or if an error occurred.
+func (it *QueryIterator) Next(item interface{}) bool {
+
+	for !it.closed && it.err == nil && len(it.items) == 0 && !it.lastPage {
+		it.fetchPage()
+	}
+	if it.closed || it.err != nil || len(it.items) == 0 {
+		return false
+	}
+
+	if err := dynamodbattribute.UnmarshalMap(it.items[0], item); err != nil {
+		it.err = err
+		it.cancel()
+		return false
+	}
+	it.items = it.items[1:]
+	return true
+}
+
+// Err returns the first error occurred while reading items.
+// Returns nil if all items have been read or if reading has been stopped by Close.
+func (it *QueryIterator) Err() error {
+	return it.err
+}
+
+// Close stops iterating. Remaining items and pages will not be read.
+func (it *QueryIterator) Close() error {
+	it.closed = true
+	it.items = nil
+	it.cancel()
+	return nil
+}
+
+// fetchPage requests the next page from DynamoDb. The context of this iterator is canceled
+// if there're no further pages or if an error occurred, because it's not needed anymore.
+func (it *QueryIterator) fetchPage() {
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		it.cancel()
+		return
+	}
+
+	result, err := it.repository.dynamoDb().QueryWithContext(it.ctx, it.input)
+	if err != nil {
+		it.err = wrapError(err, NewItemIdentifier("", it.objectType), ErrConditionFailed)
+		it.cancel()
+		return
+	}
+
+	it.repository.logger.Debugf("Query Result: %+v", result)
+	it.items = result.Items
+	it.input.ExclusiveStartKey = result.LastEvaluatedKey
+	it.lastPage = len(result.LastEvaluatedKey) == 0
+	if it.lastPage {
+		it.cancel()
+	}
+}
@@ -0,0 +1,64 @@
+package dynamodb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+func (suite *QueryTestSuite) TestQueryIteratorWithInvalidOptions() {
+
+	repo := newRepositoryForTest()
+	iter := repo.QueryIter("TestItems", WithFilter(expression.ConditionBuilder{}))
+	suite.NotNil(iter.Err())
+	suite.NotNil(iter.ctx.Err())
+	suite.False(iter.Next(&testItem{}))
+	suite.Nil(iter.Close())
+}
+
+func (suite *RepositoryTestSuite) TestQueryIterator() {
+
+	// 15 items with 100 KB each force multiple pages.
+	itemCount := 15
+	for i := 1; i <= itemCount; i++ {
+		suite.Nil(suite.repo.Add(newLargeItemForTest(100 * 1024)))
+	}
+
+	iter := suite.repo.QueryIter("TestItems")
+	count := 0
+	item := testItem{}
+	for iter.Next(&item) {
+		suite.Equal("TestItems", item.GetObjectType())
+		count++
+	}
+	suite.Nil(iter.Err())
+	suite.Equal(itemCount, count)
+	suite.Nil(iter.Close())
+
+	iter2 := suite.repo.QueryIter("TestItems")
+	suite.True(iter2.Next(&testItem{}))
+	suite.Nil(iter2.Close())
+	suite.False(iter2.Next(&testItem{}))
+	suite.Nil(iter2.Err())
+
+	iter3 := suite.repo.QueryIter("XXX")
+	suite.False(iter3.Next(&testItem{}))
+	suite.Nil(iter3.Err())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	iter4 := suite.repo.QueryIterWithContext(ctx, "TestItems")
+	suite.False(iter4.Next(&testItem{}))
+	suite.NotNil(iter4.Err())
+
+	iter5 := suite.repo.QueryIter("TestItems")
+	suite.False(iter5.Next(testItem{}))
+	suite.NotNil(iter5.Err())
+	suite.NotNil(iter5.ctx.Err())
+
+	iter6 := suite.repo.QueryIter("TestItems")
+	for iter6.Next(&testItem{}) {
+	}
+	suite.Nil(iter6.Err())
+	suite.NotNil(iter6.ctx.Err())
+}
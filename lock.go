@@ -0,0 +1,286 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DEFAULT_LOCK_WAIT_INITIAL_BACKOFF is the default delay before the first retry to obtain a lock.
+const DEFAULT_LOCK_WAIT_INITIAL_BACKOFF = 100 * time.Millisecond
+
+// DEFAULT_LOCK_WAIT_MAX_BACKOFF is the default max delay between two retries to obtain a lock.
+const DEFAULT_LOCK_WAIT_MAX_BACKOFF = 5 * time.Second
+
+// LockOption customizes obtaining a lock.
+type LockOption func(*lockOptions)
+
+// lockOptions contains all settings which can be customized by lock options.
+type lockOptions struct {
+
+	// ttl is the life time of a lock.
+	ttl time.Duration
+
+	// maxWait is the max duration to wait for a lock. Zero means no limit.
+	maxWait time.Duration
+
+	// initialBackoff is the delay before the first retry.
+	initialBackoff time.Duration
+
+	// maxBackoff is the max delay between two retries.
+	maxBackoff time.Duration
+}
+
+// WithLockTTL defines the life time of a lock. Default is the life time of locks of a repository.
+func WithLockTTL(ttl time.Duration) LockOption {
+	return func(options *lockOptions) {
+		options.ttl = ttl
+	}
+}
+
+// WithMaxWait defines how long to wait for a lock at most. Default is to wait until the context is done.
+func WithMaxWait(maxWait time.Duration) LockOption {
+	return func(options *lockOptions) {
+		options.maxWait = maxWait
+	}
+}
+
+// WithBackoff defines the delay before the first retry and the max delay between two retries.
+// Delay is doubled for each retry, with a random jitter. Non-positive values are replaced by defaults
+// and max delay is at least the initial delay.
+func WithBackoff(initialBackoff, maxBackoff time.Duration) LockOption {
+	return func(options *lockOptions) {
+		options.initialBackoff = initialBackoff
+		options.maxBackoff = maxBackoff
+	}
+}
+
+// newLockOptions returns lock options with all passed options applied.
+func (r *DynamoDbRepository) newLockOptions(opts []LockOption) *lockOptions {
+
+	options := &lockOptions{
+		ttl:            r.lockTtl,
+		initialBackoff: DEFAULT_LOCK_WAIT_INITIAL_BACKOFF,
+		maxBackoff:     DEFAULT_LOCK_WAIT_MAX_BACKOFF,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.initialBackoff <= 0 {
+		options.initialBackoff = DEFAULT_LOCK_WAIT_INITIAL_BACKOFF
+	}
+	if options.maxBackoff <= 0 {
+		options.maxBackoff = DEFAULT_LOCK_WAIT_MAX_BACKOFF
+	}
+	if options.maxBackoff < options.initialBackoff {
+		options.maxBackoff = options.initialBackoff
+	}
+	return options
+}
+
+// LockWait tries to obtain a lock for passed item until it succeeds, passed context is done or
+// a max wait defined by WithMaxWait elapses. Retries are delayed by an exponential backoff with jitter.
+// If a lock has been held by someone else until then, ErrLockHeld is returned. If it has never been seen
+// as held, the context error is returned. Other errors, except throttling, are returned immediately.
+func (r *DynamoDbRepository) LockWait(ctx context.Context, item ItemKey, opts ...LockOption) (*ItemLock, error) {
+
+	options := r.newLockOptions(opts)
+	r.logger.Debugf("Wait for lock of %s, max wait: %s", identifierAsString(item), options.maxWait)
+
+	if options.maxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.maxWait)
+		defer cancel()
+	}
+
+	lockHeld := false
+	for attempt := 0; ctx.Err() == nil; attempt++ {
+
+		itemLock, err := r.LockWithTTLWithContext(ctx, item, options.ttl)
+		if err == nil {
+			return itemLock, nil
+		}
+		if errors.Is(err, ErrLockHeld) {
+			lockHeld = true
+		} else if !errors.Is(err, ErrThrottled) {
+			if ctx.Err() != nil {
+				break
+			}
+			return nil, err
+		}
+
+		delay := backoffDelay(attempt, options.initialBackoff)
+		if delay <= 0 || delay > options.maxBackoff {
+			delay = options.maxBackoff
+		}
+		r.logger.Debugf("Lock of %s is not available, retry in %s", identifierAsString(item), delay)
+		if sleepWithContext(ctx, withJitter(delay)) != nil {
+			break
+		}
+	}
+	if !lockHeld {
+		return nil, ctx.Err()
+	}
+	return nil, newRepositoryError(ErrLockHeld, NewItemIdentifier(identifierAsString(item), lockObjectType), ctx.Err())
+}
+
+// managedLockRenewFraction defines how often a managed lock is renewed during its life time.
+const managedLockRenewFraction = 3
+
+// ManagedLock is a lock which is renewed in background until it's released or lost.
+// Use Lost to get notified if a lock can't be renewed anymore.
+type ManagedLock struct {
+
+	// repository is used to renew and release a lock.
+	repository *DynamoDbRepository
+
+	// itemLock is the current lock, guarded by mutex.
+	itemLock *ItemLock
+
+	// ttl is the life time of a lock, used for each renewal.
+	ttl time.Duration
+
+	// mutex guards item lock and error.
+	mutex sync.Mutex
+
+	// err is the reason why a lock has been lost.
+	err error
+
+	// lost is closed if a lock has been lost.
+	lost chan struct{}
+
+	// stop is closed to stop renewing a lock.
+	stop chan struct{}
+
+	// done is closed if renewing a lock has been stopped.
+	done chan struct{}
+
+	// releaseOnce ensures a lock is only released once.
+	releaseOnce sync.Once
+}
+
+// LockManaged obtains a lock for passed item and renews it in background at a third of its life time until
+// it's released. If WithMaxWait is passed, it retries to obtain a lock like LockWait, otherwise ErrLockHeld
+// is returned immediately if a lock is held by someone else.
+func (r *DynamoDbRepository) LockManaged(ctx context.Context, item ItemKey, opts ...LockOption) (*ManagedLock, error) {
+
+	options := r.newLockOptions(opts)
+	if options.ttl < time.Second {
+		return nil, fmt.Errorf("Lock TTL too short for managed locks: %s", options.ttl)
+	}
+
+	var itemLock *ItemLock
+	var err error
+	if options.maxWait > 0 {
+		itemLock, err = r.LockWait(ctx, item, opts...)
+	} else {
+		itemLock, err = r.LockWithTTLWithContext(ctx, item, options.ttl)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	managedLock := &ManagedLock{
+		repository: r,
+		itemLock:   itemLock,
+		ttl:        options.ttl,
+		lost:       make(chan struct{}),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go managedLock.renewInBackground()
+	return managedLock, nil
+}
+
+// ItemLock returns a copy of the current lock.
+func (lock *ManagedLock) ItemLock() ItemLock {
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	return *lock.itemLock
+}
+
+// Lost returns a channel which is closed if a lock has been lost, because it has expired
+// or has been taken over by someone else. Use Err to get the reason.
+func (lock *ManagedLock) Lost() <-chan struct{} {
+	return lock.lost
+}
+
+// Err returns the reason why a lock has been lost. Returns nil as long as a lock is held.
+func (lock *ManagedLock) Err() error {
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	return lock.err
+}
+
+// Release stops renewing a lock and removes it from DynamoDb.
+// If a lock has been lost before, it's not removed and the reason is returned.
+func (lock *ManagedLock) Release() error {
+
+	var err error
+	lock.releaseOnce.Do(func() {
+		close(lock.stop)
+		<-lock.done
+
+		if err = lock.Err(); err == nil {
+			itemLock := lock.ItemLock()
+			err = lock.repository.Unlock(&itemLock)
+		}
+	})
+	return err
+}
+
+// renewInBackground renews a lock periodically until it's released or lost.
+func (lock *ManagedLock) renewInBackground() {
+
+	defer close(lock.done)
+
+	interval := lock.ttl / managedLockRenewFraction
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-lock.stop:
+			return
+		case <-ticker.C:
+			if err := lock.renew(interval); err != nil {
+				lock.markAsLost(err)
+				return
+			}
+		}
+	}
+}
+
+// renew extends the life time of a lock. Returns an error if a lock has been lost.
+// Other errors are logged and renewal will be retried at next interval, as long as a lock has not expired.
+func (lock *ManagedLock) renew(timeout time.Duration) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	itemLock := lock.ItemLock()
+	expiresAt := itemLock.ExpiresAt
+	_, err := lock.repository.RenewWithTTLWithContext(ctx, &itemLock, lock.ttl)
+	if err == nil {
+		lock.mutex.Lock()
+		lock.itemLock = &itemLock
+		lock.mutex.Unlock()
+		return nil
+	}
+
+	lock.repository.logger.Error("Unable to renew lock: ", err)
+	if errors.Is(err, ErrLockLost) || time.Now().Unix() >= expiresAt {
+		return newRepositoryError(ErrLockLost, &itemLock, err)
+	}
+	return nil
+}
+
+// markAsLost keeps passed error and notifies about a lost lock.
+func (lock *ManagedLock) markAsLost(err error) {
+	lock.mutex.Lock()
+	lock.err = err
+	lock.mutex.Unlock()
+	close(lock.lost)
+}
@@ -0,0 +1,120 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LockTestSuite struct {
+	suite.Suite
+}
+
+func TestLockTestSuite(t *testing.T) {
+	suite.Run(t, new(LockTestSuite))
+}
+
+func (suite *LockTestSuite) TestLockOptions() {
+
+	repo := newRepositoryForTest()
+
+	options := repo.newLockOptions([]LockOption{})
+	suite.Equal(DEFAULT_LOCK_WAIT_INITIAL_BACKOFF, options.initialBackoff)
+	suite.Equal(DEFAULT_LOCK_WAIT_MAX_BACKOFF, options.maxBackoff)
+
+	options2 := repo.newLockOptions([]LockOption{WithBackoff(0, 0)})
+	suite.Equal(DEFAULT_LOCK_WAIT_INITIAL_BACKOFF, options2.initialBackoff)
+	suite.Equal(DEFAULT_LOCK_WAIT_MAX_BACKOFF, options2.maxBackoff)
+
+	options3 := repo.newLockOptions([]LockOption{WithBackoff(-1*time.Second, -1*time.Second)})
+	suite.Equal(DEFAULT_LOCK_WAIT_INITIAL_BACKOFF, options3.initialBackoff)
+	suite.Equal(DEFAULT_LOCK_WAIT_MAX_BACKOFF, options3.maxBackoff)
+
+	options4 := repo.newLockOptions([]LockOption{WithBackoff(10*time.Second, 1*time.Second)})
+	suite.Equal(10*time.Second, options4.initialBackoff)
+	suite.Equal(10*time.Second, options4.maxBackoff)
+}
+
+func (suite *LockTestSuite) TestLockWaitWithDoneContext() {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := newRepositoryForTest().LockWait(ctx, newItemForTest())
+	suite.True(errors.Is(err, context.Canceled))
+	suite.False(errors.Is(err, ErrLockHeld))
+}
+
+func (suite *RepositoryTestSuite) TestLockWait() {
+
+	item := newItemForTest()
+	itemLock, err := suite.repo.LockWithTTL(item, 2*time.Second)
+	suite.Nil(err)
+
+	start := time.Now()
+	_, err1 := suite.repo.LockWait(context.Background(), item, WithMaxWait(500*time.Millisecond), WithBackoff(50*time.Millisecond, 200*time.Millisecond))
+	suite.True(errors.Is(err1, ErrLockHeld))
+	suite.True(time.Since(start) < 2*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err2 := suite.repo.LockWait(ctx, item)
+	suite.True(errors.Is(err2, ErrLockHeld))
+	suite.True(errors.Is(err2, context.DeadlineExceeded))
+
+	itemLock2, err3 := suite.repo.LockWait(context.Background(), item, WithMaxWait(10*time.Second), WithLockTTL(1*time.Minute))
+	suite.Nil(err3)
+	suite.NotEqual(itemLock.LockId, itemLock2.LockId)
+	suite.True(itemLock2.ExpiresAt > time.Now().Add(30*time.Second).Unix())
+}
+
+func (suite *RepositoryTestSuite) TestManagedLock() {
+
+	item := newItemForTest()
+	managedLock, err := suite.repo.LockManaged(context.Background(), item, WithLockTTL(3*time.Second))
+	suite.Nil(err)
+	expiresAt := managedLock.ItemLock().ExpiresAt
+
+	_, err1 := suite.repo.LockManaged(context.Background(), item)
+	suite.True(errors.Is(err1, ErrLockHeld))
+
+	time.Sleep(4 * time.Second)
+	_, err2 := suite.repo.Lock(item)
+	suite.True(errors.Is(err2, ErrLockHeld))
+	suite.True(managedLock.ItemLock().ExpiresAt > expiresAt)
+	suite.Nil(managedLock.Err())
+
+	suite.Nil(managedLock.Release())
+	suite.Nil(managedLock.Release())
+
+	_, err4 := suite.repo.LockManaged(context.Background(), item, WithLockTTL(10*time.Millisecond))
+	suite.NotNil(err4)
+	itemLock, err3 := suite.repo.Lock(item)
+	suite.Nil(err3)
+	suite.Nil(suite.repo.Unlock(itemLock))
+}
+
+func (suite *RepositoryTestSuite) TestManagedLockLost() {
+
+	item := newItemForTest()
+	managedLock, err := suite.repo.LockManaged(context.Background(), item, WithLockTTL(3*time.Second), WithMaxWait(1*time.Second))
+	suite.Nil(err)
+
+	itemLock := managedLock.ItemLock()
+	suite.Nil(suite.repo.Unlock(&itemLock))
+	itemLock2, err1 := suite.repo.Lock(item)
+	suite.Nil(err1)
+
+	select {
+	case <-managedLock.Lost():
+	case <-time.After(5 * time.Second):
+		suite.Fail("Lock has not been lost.")
+	}
+	suite.True(errors.Is(managedLock.Err(), ErrLockLost))
+	suite.True(errors.Is(managedLock.Release(), ErrLockLost))
+
+	_, err2 := suite.repo.Renew(itemLock2)
+	suite.Nil(err2)
+}
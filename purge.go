@@ -0,0 +1,114 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// PurgeOption customizes deleting all items of an object type.
+type PurgeOption func(*purgeOptions)
+
+// purgeOptions contains all settings which can be customized by purge options.
+type purgeOptions struct {
+
+	// dryRun only counts items without deleting them.
+	dryRun bool
+
+	// allowLocks enables deleting all locks.
+	allowLocks bool
+
+	// progress is called after each processed page of items. Can be nil.
+	progress func(int64)
+}
+
+// WithDryRun counts all items which would be deleted, but doesn't delete them.
+func WithDryRun() PurgeOption {
+	return func(options *purgeOptions) {
+		options.dryRun = true
+	}
+}
+
+// WithLockPurge allows to delete all item locks. Without it, deleting locks is rejected.
+func WithLockPurge() PurgeOption {
+	return func(options *purgeOptions) {
+		options.allowLocks = true
+	}
+}
+
+// WithProgress defines a func which is called with the number of deleted items after each processed page.
+// In a dry run it's called with the number of items which would have been deleted.
+func WithProgress(progress func(int64)) PurgeOption {
+	return func(options *purgeOptions) {
+		options.progress = progress
+	}
+}
+
+// newPurgeOptions returns purge options with all passed options applied.
+func newPurgeOptions(opts []PurgeOption) *purgeOptions {
+
+	options := &purgeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// DeleteObjectType calls DeleteObjectTypeWithContext with context.Background().
+func (r *DynamoDbRepository) DeleteObjectType(objectType string, opts ...PurgeOption) (int64, error) {
+	return r.DeleteObjectTypeWithContext(context.Background(), objectType, opts...)
+}
+
+// DeleteObjectTypeWithContext deletes all items of passed object type using batch requests and returns the number of
+// deleted items. Deleting locks requires WithLockPurge. Stops at the first page with items which couldn't be deleted.
+func (r *DynamoDbRepository) DeleteObjectTypeWithContext(ctx context.Context, objectType string, opts ...PurgeOption) (int64, error) {
+
+	options := newPurgeOptions(opts)
+	r.logger.Debugf("Delete all items of %s, dry run: %t", objectType, options.dryRun)
+
+	if objectType == lockObjectType && !options.allowLocks {
+		return 0, fmt.Errorf("Unsupported object type for DeleteObjectType: %s", objectType)
+	}
+
+	input, err := r.newQueryInput(objectType, newReadOptions([]ReadOption{WithProjection(primaryKey.HashKey, primaryKey.RangeKey)}))
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	var deleteErr error
+	err = r.dynamoDb().QueryPagesWithContext(ctx, input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+
+		keys := []ItemKey{}
+		for _, attributes := range page.Items {
+			keys = append(keys, itemKeyFromAttributes(attributes))
+		}
+
+		if options.dryRun {
+			deleted += int64(len(keys))
+		} else {
+			deleteErr = r.DeleteAllWithContext(ctx, keys)
+			deleted += int64(len(keys) - batchFailureCount(deleteErr))
+		}
+
+		if options.progress != nil {
+			options.progress(deleted)
+		}
+		return deleteErr == nil
+	})
+	if deleteErr != nil {
+		return deleted, deleteErr
+	}
+	return deleted, wrapError(err, NewItemIdentifier("", objectType), ErrConditionFailed)
+}
+
+// batchFailureCount returns the number of failed items if passed error is a BatchError.
+func batchFailureCount(err error) int {
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		return len(batchErr.Failures)
+	}
+	return 0
+}
@@ -0,0 +1,44 @@
+package dynamodb
+
+func (suite *RepositoryTestSuite) TestDeleteObjectType() {
+
+	itemCount := 40
+	for i := 1; i <= itemCount; i++ {
+		suite.Nil(suite.repo.Add(newItemForTest()))
+	}
+	versionedItem := newVersionedItemForTest()
+	suite.Nil(suite.repo.Add(versionedItem))
+	_, err := suite.repo.Lock(newItemForTest())
+	suite.Nil(err)
+
+	progress := []int64{}
+	count, err := suite.repo.DeleteObjectType("TestItems", WithDryRun(), WithProgress(func(processed int64) {
+		progress = append(progress, processed)
+	}))
+	suite.Nil(err)
+	suite.Equal(int64(itemCount), count)
+	suite.Equal(int64(itemCount), progress[len(progress)-1])
+
+	items := []testItem{}
+	suite.Nil(suite.repo.Query("TestItems", &items))
+	suite.Len(items, itemCount)
+
+	count, err = suite.repo.DeleteObjectType("TestItems")
+	suite.Nil(err)
+	suite.Equal(int64(itemCount), count)
+
+	items2 := []testItem{}
+	suite.Nil(suite.repo.Query("TestItems", &items2))
+	suite.Len(items2, 0)
+	suite.Nil(suite.repo.Get(&testVersionedItem{ItemIdentifier: NewItemIdentifier(versionedItem.GetId(), versionedItem.GetObjectType())}))
+
+	_, err = suite.repo.DeleteObjectType(lockObjectType)
+	suite.NotNil(err)
+	lockCount, err := suite.repo.Count(lockObjectType)
+	suite.Nil(err)
+	suite.Equal(int64(1), lockCount)
+
+	count, err = suite.repo.DeleteObjectType(lockObjectType, WithLockPurge())
+	suite.Nil(err)
+	suite.Equal(int64(1), count)
+}
@@ -0,0 +1,189 @@
+package dynamodb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// Count calls CountWithContext with context.Background().
+func (r *DynamoDbRepository) Count(objectType string, opts ...ReadOption) (int64, error) {
+	return r.CountWithContext(context.Background(), objectType, opts...)
+}
+
+// CountWithContext returns the number of items for passed object type without reading them.
+// Items can be narrowed by read options, e.g. WithSortKey or WithFilter. A projection is ignored.
+func (r *DynamoDbRepository) CountWithContext(ctx context.Context, objectType string, opts ...ReadOption) (int64, error) {
+
+	r.logger.Debug("Count Items: ", objectType)
+
+	options := newReadOptions(opts)
+	options.projection = []string{}
+	input, err := r.newQueryInput(objectType, options)
+	if err != nil {
+		return 0, err
+	}
+	input.Select = aws.String(dynamodb.SelectCount)
+
+	var count int64
+	err = r.dynamoDb().QueryPagesWithContext(ctx, input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		count += aws.Int64Value(page.Count)
+		return true
+	})
+	if err != nil {
+		return 0, wrapError(err, NewItemIdentifier("", objectType), ErrConditionFailed)
+	}
+	return count, nil
+}
+
+// ReadOption customizes read requests like Query. Get and GetAll only support WithConsistentRead.
+type ReadOption func(*readOptions)
+
+// readOptions contains all settings which can be customized by read options.
+type readOptions struct {
+
+	// sortKeyCondition narrows query results by a condition for the sort key. Can be nil.
+	sortKeyCondition SortKeyCondition
+
+	// filters are applied to query results by DynamoDb, before items are returned.
+	filters []expression.ConditionBuilder
+
+	// projection is a list of attributes which should be returned. All attributes are returned if it's empty.
+	projection []string
+
+	// descending returns query results in descending order of the sort key.
+	descending bool
+
+	// consistentRead enables strongly consistent reads.
+	consistentRead bool
+}
+
+// newReadOptions returns read options with all passed options applied.
+func newReadOptions(opts []ReadOption) *readOptions {
+
+	options := &readOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithSortKey narrows query results to items whose sort key fulfills passed condition.
+func WithSortKey(condition SortKeyCondition) ReadOption {
+	return func(options *readOptions) {
+		options.sortKeyCondition = condition
+	}
+}
+
+// WithFilter applies passed condition to query results. Only items which fulfill it are returned.
+// Filters are applied by DynamoDb after reading items, so they don't reduce consumed read capacity.
+// Multiple filters are combined by AND.
+func WithFilter(condition expression.ConditionBuilder) ReadOption {
+	return func(options *readOptions) {
+		options.filters = append(options.filters, condition)
+	}
+}
+
+// WithProjection defines attributes which should be returned for each item.
+// Other attributes of passed receiver stay empty.
+func WithProjection(attributes ...string) ReadOption {
+	return func(options *readOptions) {
+		options.projection = append(options.projection, attributes...)
+	}
+}
+
+// WithDescendingOrder returns query results in descending order of item ids, e.g. newest first
+// for time based ids. Default is ascending order.
+func WithDescendingOrder() ReadOption {
+	return func(options *readOptions) {
+		options.descending = true
+	}
+}
+
+// WithConsistentRead enables strongly consistent reads for Get, GetAll and Query, so results contain
+// all writes which have been successful before. Consistent reads consume twice the read capacity.
+func WithConsistentRead() ReadOption {
+	return func(options *readOptions) {
+		options.consistentRead = true
+	}
+}
+
+// filterCondition returns all filters combined by AND. Returns false if there's no filter.
+func (options *readOptions) filterCondition() (expression.ConditionBuilder, bool) {
+
+	switch len(options.filters) {
+	case 0:
+		return expression.ConditionBuilder{}, false
+	case 1:
+		return options.filters[0], true
+	default:
+		return expression.And(options.filters[0], options.filters[1], options.filters[2:]...), true
+	}
+}
+
+// projectionBuilder returns a projection for all requested attributes. Returns false if there's no projection.
+func (options *readOptions) projectionBuilder() (expression.ProjectionBuilder, bool) {
+
+	if len(options.projection) == 0 {
+		return expression.ProjectionBuilder{}, false
+	}
+	names := []expression.NameBuilder{}
+	for _, attribute := range options.projection[1:] {
+		names = append(names, expression.Name(attribute))
+	}
+	return expression.NamesList(expression.Name(options.projection[0]), names...), true
+}
+
+// SortKeyCondition is a condition for the sort key of items, e.g. for the Id of items in a query.
+type SortKeyCondition func(expression.KeyBuilder) expression.KeyConditionBuilder
+
+// SortKeyEqual matches items whose sort key is equal to passed value.
+func SortKeyEqual(value interface{}) SortKeyCondition {
+	return func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+		return key.Equal(expression.Value(value))
+	}
+}
+
+// SortKeyBeginsWith matches items whose sort key starts with passed prefix.
+func SortKeyBeginsWith(prefix string) SortKeyCondition {
+	return func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+		return key.BeginsWith(prefix)
+	}
+}
+
+// SortKeyBetween matches items whose sort key is between passed lower and upper value, both inclusive.
+func SortKeyBetween(lower, upper interface{}) SortKeyCondition {
+	return func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+		return key.Between(expression.Value(lower), expression.Value(upper))
+	}
+}
+
+// SortKeyLessThan matches items whose sort key is less than passed value.
+func SortKeyLessThan(value interface{}) SortKeyCondition {
+	return func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+		return key.LessThan(expression.Value(value))
+	}
+}
+
+// SortKeyLessThanEqual matches items whose sort key is less than or equal to passed value.
+func SortKeyLessThanEqual(value interface{}) SortKeyCondition {
+	return func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+		return key.LessThanEqual(expression.Value(value))
+	}
+}
+
+// SortKeyGreaterThan matches items whose sort key is greater than passed value.
+func SortKeyGreaterThan(value interface{}) SortKeyCondition {
+	return func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+		return key.GreaterThan(expression.Value(value))
+	}
+}
+
+// SortKeyGreaterThanEqual matches items whose sort key is greater than or equal to passed value.
+func SortKeyGreaterThanEqual(value interface{}) SortKeyCondition {
+	return func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+		return key.GreaterThanEqual(expression.Value(value))
+	}
+}
@@ -0,0 +1,176 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/stretchr/testify/suite"
+)
+
+type QueryTestSuite struct {
+	suite.Suite
+}
+
+func TestQueryTestSuite(t *testing.T) {
+	suite.Run(t, new(QueryTestSuite))
+}
+
+func (suite *QueryTestSuite) TestNewQueryInputWithSortKey() {
+
+	repo := newRepositoryForTest()
+	input, err := repo.newQueryInput("TestItems", newReadOptions([]ReadOption{}))
+	suite.Nil(err)
+	suite.Len(input.ExpressionAttributeNames, 1)
+	suite.Len(input.ExpressionAttributeValues, 1)
+
+	for _, condition := range []SortKeyCondition{
+		SortKeyEqual("2026-10-01"),
+		SortKeyBeginsWith("2026-10"),
+		SortKeyBetween("2026-10-01", "2026-10-31"),
+		SortKeyLessThan("2026-10"),
+		SortKeyLessThanEqual("2026-10"),
+		SortKeyGreaterThan("2026-10"),
+		SortKeyGreaterThanEqual("2026-10"),
+	} {
+		input, err := repo.newQueryInput("TestItems", newReadOptions([]ReadOption{WithSortKey(condition)}))
+		suite.Nil(err)
+		suite.Len(input.ExpressionAttributeNames, 2)
+		suite.Contains(*input.KeyConditionExpression, "AND")
+	}
+}
+
+func (suite *QueryTestSuite) TestNewQueryInputWithFilterAndProjection() {
+
+	repo := newRepositoryForTest()
+	input, err := repo.newQueryInput("TestItems", newReadOptions([]ReadOption{}))
+	suite.Nil(err)
+	suite.Nil(input.FilterExpression)
+	suite.Nil(input.ProjectionExpression)
+
+	opts := []ReadOption{
+		WithFilter(expression.Name("Status").Equal(expression.Value("open"))),
+		WithFilter(expression.Name("Val2").GreaterThan(expression.Value(10))),
+		WithProjection("Id", "ObjectType"),
+		WithProjection("Status"),
+	}
+	input2, err2 := repo.newQueryInput("TestItems", newReadOptions(opts))
+	suite.Nil(err2)
+	suite.Contains(*input2.FilterExpression, "AND")
+	suite.NotNil(input2.ProjectionExpression)
+	suite.Len(input2.ExpressionAttributeNames, 4)
+}
+
+func (suite *QueryTestSuite) TestReadOrderAndConsistency() {
+
+	repo := newRepositoryForTest()
+	input, err := repo.newQueryInput("TestItems", newReadOptions([]ReadOption{}))
+	suite.Nil(err)
+	suite.True(*input.ScanIndexForward)
+	suite.False(*input.ConsistentRead)
+
+	input2, err2 := repo.newQueryInput("TestItems", newReadOptions([]ReadOption{WithDescendingOrder(), WithConsistentRead()}))
+	suite.Nil(err2)
+	suite.False(*input2.ScanIndexForward)
+	suite.True(*input2.ConsistentRead)
+
+	item := newItemForTest()
+	suite.False(*repo.newGetItemInput(item, newReadOptions([]ReadOption{})).ConsistentRead)
+	suite.True(*repo.newGetItemInput(item, newReadOptions([]ReadOption{WithConsistentRead()})).ConsistentRead)
+}
+
+func (suite *RepositoryTestSuite) TestReadInDescendingOrderWithConsistentRead() {
+
+	for _, id := range []string{"2026-10-01", "2026-10-03", "2026-10-02"} {
+		item := newItemForTest()
+		item.ItemIdentifier.Id = id
+		suite.Nil(suite.repo.Add(item))
+	}
+
+	items := []testItem{}
+	suite.Nil(suite.repo.Query("TestItems", &items, WithDescendingOrder(), WithConsistentRead()))
+	suite.Len(items, 3)
+	suite.Equal("2026-10-03", items[0].GetId())
+	suite.Equal("2026-10-01", items[2].GetId())
+
+	item := &testItem{ItemIdentifier: NewItemIdentifier("2026-10-02", "TestItems")}
+	suite.Nil(suite.repo.Get(item, WithConsistentRead()))
+	suite.Equal("xXx", item.Val1)
+
+	items2 := []testItem{}
+	notFound, err := suite.repo.GetAll([]ItemKey{item}, &items2, WithConsistentRead())
+	suite.Nil(err)
+	suite.Len(notFound, 0)
+	suite.Len(items2, 1)
+}
+
+func (suite *RepositoryTestSuite) TestQueryItemsWithFilterAndProjection() {
+
+	for i := 1; i <= 5; i++ {
+		item := newItemForTest()
+		item.Val2 = i
+		suite.Nil(suite.repo.Add(item))
+	}
+
+	items := []testItem{}
+	suite.Nil(suite.repo.Query("TestItems", &items,
+		WithFilter(expression.Name("Val2").GreaterThan(expression.Value(3))),
+		WithProjection("Id", "ObjectType", "Val2")))
+	suite.Len(items, 2)
+	for _, item := range items {
+		suite.True(item.Val2 > 3)
+		suite.Equal("", item.Val1)
+	}
+}
+
+func (suite *RepositoryTestSuite) TestQueryItemsWithSortKey() {
+
+	for _, id := range []string{"2026-09-30", "2026-10-01", "2026-10-15", "2026-10-31", "2026-11-01"} {
+		item := newItemForTest()
+		item.ItemIdentifier.Id = id
+		suite.Nil(suite.repo.Add(item))
+	}
+
+	assertQueryResult := func(condition SortKeyCondition, expectedCount int) {
+		items := []testItem{}
+		suite.Nil(suite.repo.Query("TestItems", &items, WithSortKey(condition)))
+		suite.Len(items, expectedCount)
+	}
+	assertQueryResult(SortKeyEqual("2026-10-15"), 1)
+	assertQueryResult(SortKeyBeginsWith("2026-10"), 3)
+	assertQueryResult(SortKeyBetween("2026-10-01", "2026-10-31"), 3)
+	assertQueryResult(SortKeyLessThan("2026-10-01"), 1)
+	assertQueryResult(SortKeyLessThanEqual("2026-10-01"), 2)
+	assertQueryResult(SortKeyGreaterThan("2026-10-31"), 1)
+	assertQueryResult(SortKeyGreaterThanEqual("2026-10-31"), 2)
+}
+
+func (suite *RepositoryTestSuite) TestCountItems() {
+
+	for _, id := range []string{"2026-09-30", "2026-10-01", "2026-10-15"} {
+		item := newItemForTest()
+		item.ItemIdentifier.Id = id
+		suite.Nil(suite.repo.Add(item))
+	}
+	// 15 items with 100 KB each force multiple pages.
+	for i := 1; i <= 15; i++ {
+		item := newLargeItemForTest(100 * 1024)
+		item.Val2 = i
+		suite.Nil(suite.repo.Add(item))
+	}
+
+	count, err := suite.repo.Count("TestItems")
+	suite.Nil(err)
+	suite.Equal(int64(18), count)
+
+	count, err = suite.repo.Count("TestItems", WithSortKey(SortKeyBeginsWith("2026-10")), WithProjection("Id"))
+	suite.Nil(err)
+	suite.Equal(int64(2), count)
+
+	count, err = suite.repo.Count("TestItems", WithFilter(expression.Name("Val2").LessThanEqual(expression.Value(5))))
+	suite.Nil(err)
+	suite.Equal(int64(5), count)
+
+	count, err = suite.repo.Count("XXX")
+	suite.Nil(err)
+	suite.Equal(int64(0), count)
+}
@@ -1,9 +1,11 @@
 package dynamodb
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -20,11 +22,25 @@ const DEFAULT_AWS_REGION = "eu-central-1"
 // DEFAULT_TABLENAME defines the default DynamoDb table name.
 const DEFAULT_TABLENAME = "<DynamoDbTableNotSet>"
 
+// DEFAULT_LOCK_TTL defines the life time of locks if nothing has been specified by config.
+const DEFAULT_LOCK_TTL = 5 * time.Minute
+
 // lockObjectType is the object type used for locks.
 const lockObjectType = "OBJECTLOCK"
 
-// Add will create a new item or update an existing item in DynamoDb.
+// primaryKey defines the attributes of the primary key of a table.
+var primaryKey = SecondaryIndex{HashKey: "ObjectType", RangeKey: "Id"}
+
+// versionAttribute is the name of the attribute which contains the version of an item.
+const versionAttribute = "Version"
+
+// Add calls AddWithContext with context.Background().
 func (r *DynamoDbRepository) Add(item ItemKey) error {
+	return r.AddWithContext(context.Background(), item)
+}
+
+// AddWithContext will create a new item or update an existing item in DynamoDb.
+func (r *DynamoDbRepository) AddWithContext(ctx context.Context, item ItemKey) error {
 
 	r.logger.Debug("Add Item: ", identifierAsString(item))
 
@@ -32,18 +48,68 @@ func (r *DynamoDbRepository) Add(item ItemKey) error {
 		return fmt.Errorf("Unsupported object type for Add: %s", item.GetObjectType())
 	}
 
+	if versionedItem, ok := item.(Versioned); ok {
+		return r.addVersionedItem(ctx, item, versionedItem)
+	}
+
 	av, err := dynamodbattribute.MarshalMap(item)
 	r.logger.Debugf("AttributeValue: %+v", av)
 	if err == nil {
 		input := &dynamodb.PutItemInput{Item: av, TableName: r.tableName}
-		_, err = r.dynamoDb().PutItem(input)
+		_, err = r.dynamoDb().PutItemWithContext(ctx, input)
 	}
-	return err
+	return wrapError(err, item, ErrConditionFailed)
+}
+
+// addVersionedItem increments the version of passed item and writes it to DynamoDb, if the version
+// of a stored item is still the previous one. Otherwise ErrVersionConflict is returned and
+// the version of passed item stays unchanged.
+func (r *DynamoDbRepository) addVersionedItem(ctx context.Context, item ItemKey, versionedItem Versioned) error {
+
+	previousVersion := versionedItem.GetVersion()
+	versionedItem.SetVersion(previousVersion + 1)
+
+	input, err := r.newPutItemInputForVersion(item, previousVersion)
+	if err == nil {
+		_, err = r.dynamoDb().PutItemWithContext(ctx, input)
+	}
+	if err != nil {
+		versionedItem.SetVersion(previousVersion)
+	}
+	return wrapError(err, item, ErrVersionConflict)
+}
+
+// AddIfNotExists calls AddIfNotExistsWithContext with context.Background().
+func (r *DynamoDbRepository) AddIfNotExists(item ItemKey) error {
+	return r.AddIfNotExistsWithContext(context.Background(), item)
+}
+
+// AddIfNotExistsWithContext will create a new item in DynamoDb. If an item with same key already exists
+// it will not be overwritten and ErrAlreadyExists is returned.
+func (r *DynamoDbRepository) AddIfNotExistsWithContext(ctx context.Context, item ItemKey) error {
+
+	r.logger.Debug("Add new Item: ", identifierAsString(item))
+
+	if item.GetObjectType() == lockObjectType {
+		return fmt.Errorf("Unsupported object type for Add: %s", item.GetObjectType())
+	}
+
+	input, err := r.newPutItemInputIfNotExists(item)
+	if err == nil {
+		_, err = r.dynamoDb().PutItemWithContext(ctx, input)
+	}
+	return wrapError(err, item, ErrAlreadyExists)
+}
+
+// Get calls GetWithContext with context.Background().
+func (r *DynamoDbRepository) Get(item ItemKey, opts ...ReadOption) error {
+	return r.GetWithContext(context.Background(), item, opts...)
 }
 
-// Get will try to read an item from DynamDb by passed item key.
+// GetWithContext will try to read an item from DynamDb by passed item key.
 // Passed item have to be a pointer, because it will unmarshal DynamiDb item values into it.
-func (r *DynamoDbRepository) Get(item ItemKey) error {
+// Use WithConsistentRead to get a strongly consistent read.
+func (r *DynamoDbRepository) GetWithContext(ctx context.Context, item ItemKey, opts ...ReadOption) error {
 
 	r.logger.Debug("Get item: ", identifierAsString(item))
 
@@ -53,34 +119,45 @@ func (r *DynamoDbRepository) Get(item ItemKey) error {
 		return errors.New(msg)
 	}
 
-	result, err := r.dynamoDb().GetItem(r.newGetItemInput(item))
+	result, err := r.dynamoDb().GetItemWithContext(ctx, r.newGetItemInput(item, newReadOptions(opts)))
 	if err == nil {
 
 		r.logger.Debugf("DynamoDb Response for %s is: %+v", identifierAsString(item), result.Item)
 		if len(result.Item) == 0 {
-			msg := fmt.Sprintf("Not found: %s\n", identifierAsString(item))
-			r.logger.Info(msg)
-			return errors.New(msg)
+			r.logger.Info("Not found: ", identifierAsString(item))
+			return newRepositoryError(ErrNotFound, item, nil)
 		}
 		return dynamodbattribute.UnmarshalMap(result.Item, item)
 
 	}
-	return err
+	return wrapError(err, item, ErrConditionFailed)
 }
 
-// Delete will try to delete an item from DynamoDb item identified by passed item key.
+// Delete calls DeleteWithContext with context.Background().
 func (r *DynamoDbRepository) Delete(item ItemKey) error {
+	return r.DeleteWithContext(context.Background(), item)
+}
+
+// DeleteWithContext will try to delete an item from DynamoDb item identified by passed item key.
+func (r *DynamoDbRepository) DeleteWithContext(ctx context.Context, item ItemKey) error {
 
 	r.logger.Debug("Delete Item: ", identifierAsString(item))
 
-	_, err := r.dynamoDb().DeleteItem(r.newDeleteItemInput(item))
-	return err
+	_, err := r.dynamoDb().DeleteItemWithContext(ctx, r.newDeleteItemInput(item))
+	return wrapError(err, item, ErrConditionFailed)
+}
+
+// Query calls QueryWithContext with context.Background().
+func (r *DynamoDbRepository) Query(objectType string, receiver interface{}, opts ...ReadOption) error {
+	return r.QueryWithContext(context.Background(), objectType, receiver, opts...)
 }
 
-// Query will list items for a specific object type. Receiver have to be a slice of expected type
+// QueryWithContext will list items for a specific object type. Receiver have to be a slice of expected type
 // and will be used to unmarshal query result. So please pass it as a pointer.
 // If there're no items for passed object type no error is returned, passed slice will stay empty.
-func (r *DynamoDbRepository) Query(objectType string, receiver interface{}) error {
+// Query follows all result pages, so all items of passed object type will be returned.
+// Results can be narrowed by read options, e.g. by a condition for item ids using WithSortKey.
+func (r *DynamoDbRepository) QueryWithContext(ctx context.Context, objectType string, receiver interface{}, opts ...ReadOption) error {
 
 	r.logger.Debug("Query Items: ", objectType)
 
@@ -90,80 +167,172 @@ func (r *DynamoDbRepository) Query(objectType string, receiver interface{}) erro
 		return errors.New(msg)
 	}
 
-	result, err := r.dynamoDb().Query(r.newQueryInput(objectType))
-	r.logger.Debugf("Query Result: %+v", result)
-	if err == nil {
-		err = dynamodbattribute.UnmarshalListOfMaps(result.Items, receiver)
-		r.logger.Debugf("List Response: %+v", receiver)
+	input, err := r.newQueryInput(objectType, newReadOptions(opts))
+	if err != nil {
+		return err
 	}
-	return err
+	return wrapError(r.queryAllPages(ctx, input, receiver), NewItemIdentifier("", objectType), ErrConditionFailed)
 }
 
-// Lock will try to obtain a lock passed item. Default life time of a lock is 5 min.
+// Lock calls LockWithTTLWithContext with context.Background() and the default life time of locks.
 func (r *DynamoDbRepository) Lock(item ItemKey) (*ItemLock, error) {
+	return r.LockWithTTLWithContext(context.Background(), item, r.lockTtl)
+}
+
+// LockWithContext will try to obtain a lock passed item. Default life time of a lock is 5 min,
+// it can be changed by config key aws.dynamodb.locks.ttl.
+func (r *DynamoDbRepository) LockWithContext(ctx context.Context, item ItemKey) (*ItemLock, error) {
+	return r.LockWithTTLWithContext(ctx, item, r.lockTtl)
+}
+
+// LockWithTTL calls LockWithTTLWithContext with context.Background().
+func (r *DynamoDbRepository) LockWithTTL(item ItemKey, ttl time.Duration) (*ItemLock, error) {
+	return r.LockWithTTLWithContext(context.Background(), item, ttl)
+}
+
+// LockWithTTLWithContext will try to obtain a lock passed item with given life time.
+// Returns an error if passed life time is not positive.
+func (r *DynamoDbRepository) LockWithTTLWithContext(ctx context.Context, item ItemKey, ttl time.Duration) (*ItemLock, error) {
 
-	itemLock := r.newObjectLockForItem(item)
+	if ttl <= 0 {
+		return nil, fmt.Errorf("Invalid lock TTL: %s", ttl)
+	}
+
+	itemLock := r.newObjectLockForItem(item, ttl)
 	input := r.newPutItemInputForLock(&itemLock)
-	if _, err := r.dynamoDb().PutItem(input); err == nil {
+	if _, err := r.dynamoDb().PutItemWithContext(ctx, input); err == nil {
 		return &itemLock, nil
 	} else {
-		return nil, err
+		return nil, wrapError(err, &itemLock, ErrLockHeld)
 	}
 }
 
-// Renew can be used to extend life time of a lock.
+// Renew calls RenewWithTTLWithContext with context.Background() and the default life time of locks.
 func (r *DynamoDbRepository) Renew(itemLock *ItemLock) (*ItemLock, error) {
+	return r.RenewWithTTLWithContext(context.Background(), itemLock, r.lockTtl)
+}
 
-	itemLock.ExpiresAt = r.newLockExpiration()
+// RenewWithContext can be used to extend life time of a lock by the default life time of locks.
+func (r *DynamoDbRepository) RenewWithContext(ctx context.Context, itemLock *ItemLock) (*ItemLock, error) {
+	return r.RenewWithTTLWithContext(ctx, itemLock, r.lockTtl)
+}
+
+// RenewWithTTL calls RenewWithTTLWithContext with context.Background().
+func (r *DynamoDbRepository) RenewWithTTL(itemLock *ItemLock, ttl time.Duration) (*ItemLock, error) {
+	return r.RenewWithTTLWithContext(context.Background(), itemLock, ttl)
+}
+
+// RenewWithTTLWithContext can be used to extend life time of a lock by passed duration.
+// Returns an error if passed duration is not positive.
+func (r *DynamoDbRepository) RenewWithTTLWithContext(ctx context.Context, itemLock *ItemLock, ttl time.Duration) (*ItemLock, error) {
+
+	if ttl <= 0 {
+		return nil, fmt.Errorf("Invalid lock TTL: %s", ttl)
+	}
+
+	itemLock.ExpiresAt = newLockExpiration(ttl)
 	input := r.newPutItemInputForRenew(itemLock)
-	if _, err := r.dynamoDb().PutItem(input); err == nil {
+	if _, err := r.dynamoDb().PutItemWithContext(ctx, input); err == nil {
 		return itemLock, nil
 	} else {
-		return nil, err
+		return nil, wrapError(err, itemLock, ErrLockLost)
 	}
 }
 
-// Unlock will remove given lock from DynamoDb.
+// Unlock calls UnlockWithContext with context.Background().
 func (r *DynamoDbRepository) Unlock(itemLock *ItemLock) error {
+	return r.UnlockWithContext(context.Background(), itemLock)
+}
+
+// UnlockWithContext will remove given lock from DynamoDb. A lock is only removed if it's still owned by the caller,
+// otherwise ErrLockLost is returned, because it has been removed or taken over by someone else.
+func (r *DynamoDbRepository) UnlockWithContext(ctx context.Context, itemLock *ItemLock) error {
 
-	return r.Delete(itemLock)
+	r.logger.Debug("Unlock: ", identifierAsString(itemLock))
+
+	_, err := r.dynamoDb().DeleteItemWithContext(ctx, r.newDeleteItemInputForLock(itemLock))
+	return wrapError(err, itemLock, ErrLockLost)
 }
 
 // dynamoDb creates a DynamoDb client. Uses a singleton pattern which creates the client only once.
+// It's safe for concurrent use, e.g. by scan workers or the renewal of managed locks.
 func (r *DynamoDbRepository) dynamoDb() *dynamodb.DynamoDB {
 
-	if r.dynamoDbClient == nil {
+	r.dynamoDbClientOnce.Do(func() {
 		sess := session.Must(session.NewSession(r.config))
 		r.dynamoDbClient = dynamodb.New(sess)
-	}
+	})
 	return r.dynamoDbClient
 }
 
-// newGetItemInput creates a new DynamoDb GetItemInout for passed item.
-func (r *DynamoDbRepository) newGetItemInput(item ItemKey) *dynamodb.GetItemInput {
+// newItemKey returns the primary key attributes of passed item.
+func (r *DynamoDbRepository) newItemKey(item ItemKey) map[string]*dynamodb.AttributeValue {
 
 	dynamodbKey, _ := dynamodbattribute.MarshalMap(NewItemIdentifier(item.GetId(), item.GetObjectType()))
+	return dynamodbKey
+}
+
+// newGetItemInput creates a new DynamoDb GetItemInout for passed item.
+func (r *DynamoDbRepository) newGetItemInput(item ItemKey, options *readOptions) *dynamodb.GetItemInput {
+
 	return &dynamodb.GetItemInput{
-		Key:       dynamodbKey,
-		TableName: r.tableName,
+		Key:            r.newItemKey(item),
+		TableName:      r.tableName,
+		ConsistentRead: aws.Bool(options.consistentRead),
 	}
 }
 
 // newDeleteItemInput creates a new DynamoDb DeleteItemInout for passed item.
 func (r *DynamoDbRepository) newDeleteItemInput(item ItemKey) *dynamodb.DeleteItemInput {
 
-	dynamodbKey, _ := dynamodbattribute.MarshalMap(NewItemIdentifier(item.GetId(), item.GetObjectType()))
 	return &dynamodb.DeleteItemInput{
-		Key:       dynamodbKey,
+		Key:       r.newItemKey(item),
 		TableName: r.tableName,
 	}
 }
 
+// queryAllPages reads all pages for passed query input and unmarshals their items into given receiver.
+func (r *DynamoDbRepository) queryAllPages(ctx context.Context, input *dynamodb.QueryInput, receiver interface{}) error {
+
+	items := []map[string]*dynamodb.AttributeValue{}
+	err := r.dynamoDb().QueryPagesWithContext(ctx, input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		r.logger.Debugf("Query Result: %+v", page)
+		items = append(items, page.Items...)
+		return true
+	})
+	if err == nil {
+		err = dynamodbattribute.UnmarshalListOfMaps(items, receiver)
+		r.logger.Debugf("List Response: %+v", receiver)
+	}
+	return err
+}
+
 // newQueryInput creates a new query input for AWS DynamoDb.
-func (r *DynamoDbRepository) newQueryInput(objectType string) *dynamodb.QueryInput {
+func (r *DynamoDbRepository) newQueryInput(objectType string, options *readOptions) (*dynamodb.QueryInput, error) {
+	return r.newQueryInputForIndex(primaryKey, objectType, options)
+}
 
-	keyCondition := expression.Key("ObjectType").Equal(expression.Value(objectType))
-	expr, _ := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+// newQueryInputForIndex creates a new query input for passed index and hash key value.
+func (r *DynamoDbRepository) newQueryInputForIndex(index SecondaryIndex, hashKeyValue interface{}, options *readOptions) (*dynamodb.QueryInput, error) {
+
+	keyCondition := expression.Key(index.HashKey).Equal(expression.Value(hashKeyValue))
+	if options.sortKeyCondition != nil {
+		if index.RangeKey == "" {
+			return nil, fmt.Errorf("No range key defined for index: %s", index.Name)
+		}
+		keyCondition = expression.KeyAnd(keyCondition, options.sortKeyCondition(expression.Key(index.RangeKey)))
+	}
+	builder := expression.NewBuilder().WithKeyCondition(keyCondition)
+	if filter, ok := options.filterCondition(); ok {
+		builder = builder.WithFilter(filter)
+	}
+	if projection, ok := options.projectionBuilder(); ok {
+		builder = builder.WithProjection(projection)
+	}
+	expr, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
 	r.logger.Debugf("Key expression: %+v", expr)
 
 	expressionAttributeNames := expr.Names()
@@ -171,26 +340,105 @@ func (r *DynamoDbRepository) newQueryInput(objectType string) *dynamodb.QueryInp
 	r.logger.Debugf("Expr names: %+v", expressionAttributeNames)
 	r.logger.Debugf("Expr values: %+v", expressionAttributeValues)
 
-	return &dynamodb.QueryInput{
+	input := &dynamodb.QueryInput{
 		ExpressionAttributeNames:  expressionAttributeNames,
 		ExpressionAttributeValues: expressionAttributeValues,
 		TableName:                 r.tableName,
 		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		ScanIndexForward:          aws.Bool(!options.descending),
+		ConsistentRead:            aws.Bool(options.consistentRead),
+	}
+	if index.Name != "" {
+		input.IndexName = aws.String(index.Name)
+	}
+	return input, nil
+}
+
+// newPutItemInputIfNotExists creates a new put item input which fails if an item with same key already exists.
+func (r *DynamoDbRepository) newPutItemInputIfNotExists(item ItemKey) (*dynamodb.PutItemInput, error) {
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return nil, err
 	}
+	return &dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           r.tableName,
+		ConditionExpression: aws.String("attribute_not_exists(Id) AND attribute_not_exists(ObjectType)"),
+	}, nil
+}
+
+// newPutItemInputForVersion creates a new put item input which fails if the version of an existing item
+// is not equal to passed previous version. Items without a version are treated as version 0.
+func (r *DynamoDbRepository) newPutItemInputForVersion(item ItemKey, previousVersion int64) (*dynamodb.PutItemInput, error) {
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersionAttribute(item, av, previousVersion+1); err != nil {
+		return nil, err
+	}
+
+	expr, err := expression.NewBuilder().WithCondition(newVersionCondition(previousVersion)).Build()
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.PutItemInput{
+		Item:                      av,
+		TableName:                 r.tableName,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}, nil
+}
+
+// newVersionCondition returns a condition which is fulfilled if a stored item has passed version.
+// Items without a version are treated as version 0.
+func newVersionCondition(previousVersion int64) expression.ConditionBuilder {
+
+	condition := expression.Name(versionAttribute).Equal(expression.Value(previousVersion))
+	if previousVersion == 0 {
+		condition = expression.Or(expression.Name(versionAttribute).AttributeNotExists(), condition)
+	}
+	return condition
+}
+
+// validateVersionedItem ensures passed item stores its current version in the version attribute.
+func validateVersionedItem(item ItemKey, versionedItem Versioned) error {
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+	return checkVersionAttribute(item, av, versionedItem.GetVersion())
+}
+
+// checkVersionAttribute returns an error if passed item values don't contain given version in the version attribute.
+// Otherwise a version condition would never or always be fulfilled.
+func checkVersionAttribute(item ItemKey, av map[string]*dynamodb.AttributeValue, version int64) error {
+
+	if attr, ok := av[versionAttribute]; ok && aws.StringValue(attr.N) == strconv.FormatInt(version, 10) {
+		return nil
+	}
+	return fmt.Errorf("Versioned item has to store its version in attribute %s: %s", versionAttribute, identifierAsString(item))
 }
 
 // newPutItemInputForLock creates a new conditional put item input for a lock item.
+// A lock can only be obtained if there's no lock for an item or if an existing lock has expired.
 func (r *DynamoDbRepository) newPutItemInputForLock(itemLock *ItemLock) *dynamodb.PutItemInput {
 
 	dynamodbLockData, _ := dynamodbattribute.MarshalMap(itemLock)
 
 	expressionAttributeValues := make(map[string]*dynamodb.AttributeValue)
-	ttlAttribute, _ := dynamodbattribute.Marshal(itemLock.ExpiresAt)
-	expressionAttributeValues[":ExpiresAt"] = ttlAttribute
+	nowAttribute, _ := dynamodbattribute.Marshal(time.Now().Unix())
+	expressionAttributeValues[":Now"] = nowAttribute
 	return &dynamodb.PutItemInput{
 		Item:                      dynamodbLockData,
 		TableName:                 r.tableName,
-		ConditionExpression:       aws.String("(attribute_not_exists(Id) AND attribute_not_exists(ObjectType)) or ExpiresAt < :ExpiresAt"),
+		ConditionExpression:       aws.String("(attribute_not_exists(Id) AND attribute_not_exists(ObjectType)) or ExpiresAt < :Now"),
 		ExpressionAttributeValues: expressionAttributeValues,
 	}
 }
@@ -211,16 +459,31 @@ func (r *DynamoDbRepository) newPutItemInputForRenew(itemLock *ItemLock) *dynamo
 	}
 }
 
+// newDeleteItemInputForLock creates a new conditional delete item input for a lock item,
+// which fails if the lock is owned by someone else.
+func (r *DynamoDbRepository) newDeleteItemInputForLock(itemLock *ItemLock) *dynamodb.DeleteItemInput {
+
+	expressionAttributeValues := make(map[string]*dynamodb.AttributeValue)
+	attrLockId, _ := dynamodbattribute.Marshal(itemLock.LockId)
+	expressionAttributeValues[":LockId"] = attrLockId
+	return &dynamodb.DeleteItemInput{
+		Key:                       r.newItemKey(itemLock),
+		TableName:                 r.tableName,
+		ConditionExpression:       aws.String("LockId = :LockId"),
+		ExpressionAttributeValues: expressionAttributeValues,
+	}
+}
+
 // newObjectLockForItem returns a lock object.
-func (r *DynamoDbRepository) newObjectLockForItem(item ItemKey) ItemLock {
+func (r *DynamoDbRepository) newObjectLockForItem(item ItemKey, ttl time.Duration) ItemLock {
 	return ItemLock{
 		ItemIdentifier: NewItemIdentifier(identifierAsString(item), lockObjectType),
-		ExpiresAt:      r.newLockExpiration(),
+		ExpiresAt:      newLockExpiration(ttl),
 		LockId:         utils.NewId(),
 	}
 }
 
-// newLockExpiration returns the new expiration time of a lock.
-func (r *DynamoDbRepository) newLockExpiration() int64 {
-	return time.Now().Add(r.lockTtl).Unix()
+// newLockExpiration returns the new expiration time of a lock with passed life time.
+func newLockExpiration(ttl time.Duration) int64 {
+	return time.Now().Add(ttl).Unix()
 }
@@ -1,9 +1,12 @@
 package dynamodb
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"github.com/stretchr/testify/suite"
 	testutils "github.com/tommzn/aws-dynamodb/testing"
 	config "github.com/tommzn/go-config"
@@ -58,7 +61,7 @@ func (suite *RepositoryTestSuite) TestCrudActions() {
 	suite.Equal(item.Val3.Format(time.RFC3339), item3.Val3.Format(time.RFC3339))
 
 	suite.Nil(suite.repo.Delete(item))
-	suite.NotNil(suite.repo.Get(item))
+	suite.True(errors.Is(suite.repo.Get(item), ErrNotFound))
 
 	suite.NotNil(suite.repo.Get(*item))
 
@@ -82,18 +85,25 @@ func (suite *RepositoryTestSuite) TestObjectLock() {
 	suite.Nil(err1)
 	suite.True(itemLock.ExpiresAt > expiresAt)
 
+	lockId := itemLock.LockId
 	itemLock.LockId = utils.NewId()
 	_, err1_1 := suite.repo.Renew(itemLock)
 	suite.NotNil(err1_1)
+	suite.True(errors.Is(err1_1, ErrLockLost))
 
 	itemLock2, err2 := suite.repo.Lock(item)
 	suite.NotNil(err2)
+	suite.True(errors.Is(err2, ErrLockHeld))
 	suite.Nil(itemLock2)
 
+	suite.True(errors.Is(suite.repo.Unlock(itemLock), ErrLockLost))
+
+	itemLock.LockId = lockId
 	suite.Nil(suite.repo.Unlock(itemLock))
 
 	_, err3 := suite.repo.Renew(itemLock)
 	suite.NotNil(err3)
+	suite.True(errors.Is(suite.repo.Unlock(itemLock), ErrLockLost))
 
 	itemLock4, err4 := suite.repo.Lock(item)
 	suite.Nil(err4)
@@ -119,6 +129,64 @@ func (suite *RepositoryTestSuite) TestLockAfterExpiration() {
 	suite.NotNil(itemLock2)
 }
 
+func (suite *RepositoryTestSuite) TestLockIsNotTakenOverBeforeExpiration() {
+
+	item := newItemForTest()
+	itemLock, err := suite.repo.Lock(item)
+	suite.Nil(err)
+	suite.NotNil(itemLock)
+
+	// New lock expiration is later than expiration of existing lock, but existing lock is still valid.
+	time.Sleep(1100 * time.Millisecond)
+	itemLock2, err2 := suite.repo.Lock(item)
+	suite.True(errors.Is(err2, ErrLockHeld))
+	suite.Nil(itemLock2)
+
+	_, err3 := suite.repo.Renew(itemLock)
+	suite.Nil(err3)
+}
+
+func (suite *RepositoryTestSuite) TestLockWithTTL() {
+
+	item := newItemForTest()
+	itemLock, err := suite.repo.LockWithTTL(item, 1*time.Second)
+	suite.Nil(err)
+	suite.True(itemLock.ExpiresAt <= time.Now().Add(1*time.Second).Unix())
+
+	_, err1 := suite.repo.Lock(item)
+	suite.True(errors.Is(err1, ErrLockHeld))
+
+	time.Sleep(2 * time.Second)
+	itemLock2, err2 := suite.repo.LockWithTTL(item, 1*time.Hour)
+	suite.Nil(err2)
+
+	_, err3 := suite.repo.RenewWithTTL(itemLock, 1*time.Hour)
+	suite.True(errors.Is(err3, ErrLockLost))
+
+	itemLock2, err4 := suite.repo.RenewWithTTL(itemLock2, 2*time.Hour)
+	suite.Nil(err4)
+	suite.True(itemLock2.ExpiresAt > time.Now().Add(1*time.Hour).Unix())
+}
+
+func (suite *RepositoryTestSuite) TestUnlockTakenOverLock() {
+
+	suite.repo.(*DynamoDbRepository).lockTtl = 1 * time.Second
+
+	item := newItemForTest()
+	itemLock, err := suite.repo.Lock(item)
+	suite.Nil(err)
+
+	time.Sleep(2 * time.Second)
+	itemLock2, err2 := suite.repo.LockWithTTL(item, 1*time.Hour)
+	suite.Nil(err2)
+
+	suite.True(errors.Is(suite.repo.Unlock(itemLock), ErrLockLost))
+	_, err3 := suite.repo.Lock(item)
+	suite.True(errors.Is(err3, ErrLockHeld))
+
+	suite.Nil(suite.repo.Unlock(itemLock2))
+}
+
 func (suite *RepositoryTestSuite) TestWithErrors() {
 
 	item := newItemForTest()
@@ -142,3 +210,129 @@ func (suite *RepositoryTestSuite) TestQueryItems() {
 
 	suite.NotNil(suite.repo.Query("XXX", []testItem{}))
 }
+
+func (suite *RepositoryTestSuite) TestQueryItemsWithPagination() {
+
+	// A single query response is limited to 1 MB, 15 items with 100 KB each force multiple pages.
+	itemCount := 15
+	for i := 1; i <= itemCount; i++ {
+		suite.Nil(suite.repo.Add(newLargeItemForTest(100 * 1024)))
+	}
+
+	items := []testItem{}
+	suite.Nil(suite.repo.Query("TestItems", &items))
+	suite.Len(items, itemCount)
+}
+
+func (suite *RepositoryTestSuite) TestActionsWithContext() {
+
+	ctx := context.Background()
+	item := newItemForTest()
+	suite.Nil(suite.repo.AddWithContext(ctx, item))
+
+	item2 := newTestItemWithoutValues(item)
+	suite.Nil(suite.repo.GetWithContext(ctx, item2))
+	suite.Equal(item.Val1, item2.Val1)
+
+	items := []testItem{}
+	suite.Nil(suite.repo.QueryWithContext(ctx, "TestItems", &items))
+	suite.Len(items, 1)
+
+	itemLock, err := suite.repo.LockWithContext(ctx, item)
+	suite.Nil(err)
+	_, err = suite.repo.RenewWithContext(ctx, itemLock)
+	suite.Nil(err)
+	suite.Nil(suite.repo.UnlockWithContext(ctx, itemLock))
+
+	suite.Nil(suite.repo.DeleteWithContext(ctx, item))
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	suite.NotNil(suite.repo.AddWithContext(canceledCtx, item))
+	suite.NotNil(suite.repo.GetWithContext(canceledCtx, item2))
+	suite.NotNil(suite.repo.QueryWithContext(canceledCtx, "TestItems", &items))
+}
+
+func (suite *RepositoryTestSuite) TestTransaction() {
+
+	item1 := newItemForTest()
+	item2 := newItemForTest()
+	suite.Nil(suite.repo.NewTransaction().Put(item1).Put(item2).Commit())
+
+	items := []testItem{}
+	suite.Nil(suite.repo.Query("TestItems", &items))
+	suite.Len(items, 2)
+
+	item3 := newItemForTest()
+	err := suite.repo.NewTransaction().
+		Put(item3).
+		Update(item1, NewUpdateSpec().Set("Val1", "yYy")).
+		ConditionCheck(item2, expression.Name("Val2").Equal(expression.Value(-1))).
+		Commit()
+	suite.True(errors.Is(err, ErrConditionFailed))
+
+	var transactionErr *TransactionError
+	suite.True(errors.As(err, &transactionErr))
+	suite.Len(transactionErr.Failures, 1)
+
+	suite.True(errors.Is(suite.repo.Get(newTestItemWithoutValues(item3)), ErrNotFound))
+
+	item4 := newTestItemWithoutValues(item1)
+	suite.Nil(suite.repo.Get(item4))
+	suite.Equal(item1.Val1, item4.Val1)
+
+	suite.Nil(suite.repo.NewTransaction().Delete(item1).Delete(item2).Commit())
+	items2 := []testItem{}
+	suite.Nil(suite.repo.Query("TestItems", &items2))
+	suite.Len(items2, 0)
+}
+
+func (suite *RepositoryTestSuite) TestAddIfNotExists() {
+
+	item := newItemForTest()
+	suite.Nil(suite.repo.AddIfNotExists(item))
+
+	item2 := newItemForTest()
+	item2.ItemIdentifier = NewItemIdentifier(item.GetId(), item.GetObjectType())
+	item2.Val1 = "yYy"
+	err := suite.repo.AddIfNotExists(item2)
+	suite.True(errors.Is(err, ErrAlreadyExists))
+
+	item3 := newTestItemWithoutValues(item)
+	suite.Nil(suite.repo.Get(item3))
+	suite.Equal(item.Val1, item3.Val1)
+
+	itemLock := &ItemLock{ItemIdentifier: NewItemIdentifier("id-1", lockObjectType)}
+	suite.NotNil(suite.repo.AddIfNotExists(itemLock))
+}
+
+func (suite *RepositoryTestSuite) TestVersionedItems() {
+
+	item := newVersionedItemForTest()
+	suite.Nil(suite.repo.Add(item))
+	suite.Equal(int64(1), item.GetVersion())
+
+	item1 := &testVersionedItem{ItemIdentifier: NewItemIdentifier(item.GetId(), item.GetObjectType())}
+	suite.Nil(suite.repo.Get(item1))
+	item2 := &testVersionedItem{ItemIdentifier: NewItemIdentifier(item.GetId(), item.GetObjectType())}
+	suite.Nil(suite.repo.Get(item2))
+	suite.Equal(int64(1), item2.GetVersion())
+
+	item1.Val1 = "yYy"
+	suite.Nil(suite.repo.Add(item1))
+	suite.Equal(int64(2), item1.GetVersion())
+
+	item2.Val1 = "zZz"
+	err := suite.repo.Add(item2)
+	suite.True(errors.Is(err, ErrVersionConflict))
+	suite.Equal(int64(1), item2.GetVersion())
+
+	item3 := &testVersionedItem{ItemIdentifier: NewItemIdentifier(item.GetId(), item.GetObjectType())}
+	suite.Nil(suite.repo.Get(item3))
+	suite.Equal(item1.Val1, item3.Val1)
+	suite.Equal(int64(2), item3.GetVersion())
+
+	item4 := newVersionedItemForTest()
+	item4.ItemIdentifier = NewItemIdentifier(item.GetId(), item.GetObjectType())
+	suite.True(errors.Is(suite.repo.Add(item4), ErrVersionConflict))
+}
@@ -0,0 +1,178 @@
+package dynamodb
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// maxTotalSegments is the max number of segments DynamoDb accepts for a scan.
+const maxTotalSegments = 1000000
+
+// ScanItem is a single item read by Scan. It provides the key of an item and can decode all item values.
+type ScanItem struct {
+
+	// ObjectType of an item.
+	ObjectType string
+
+	// Id of an item.
+	Id string
+
+	// attributes contains all values of an item.
+	attributes map[string]*dynamodb.AttributeValue
+}
+
+// GetId returns the id of a scanned item.
+func (item *ScanItem) GetId() string {
+	return item.Id
+}
+
+// GetObjectType returns the object type of a scanned item.
+func (item *ScanItem) GetObjectType() string {
+	return item.ObjectType
+}
+
+// Decode unmarshals all values of a scanned item into passed receiver, which have to be a pointer.
+func (item *ScanItem) Decode(receiver interface{}) error {
+	return dynamodbattribute.UnmarshalMap(item.attributes, receiver)
+}
+
+// ScanHandler is called for each item read by Scan. If it returns an error, Scan will stop.
+// A handler is called concurrently by all workers of a scan, so it has to be safe for concurrent use.
+type ScanHandler func(*ScanItem) error
+
+// ScanOption customizes a scan.
+type ScanOption func(*scanOptions)
+
+// scanOptions contains all settings which can be customized by scan options.
+type scanOptions struct {
+
+	// totalSegments is the number of segments a table is split into.
+	totalSegments int64
+
+	// workers is the number of goroutines which scan segments in parallel.
+	workers int
+}
+
+// WithTotalSegments splits a scan into passed number of segments, which can be scanned in parallel.
+// Default is a single segment, at most 1,000,000 segments are used.
+func WithTotalSegments(totalSegments int64) ScanOption {
+	return func(options *scanOptions) {
+		options.totalSegments = totalSegments
+	}
+}
+
+// WithWorkers defines the number of goroutines which scan segments in parallel.
+// Default is one worker per segment, it's never more than the number of segments.
+func WithWorkers(workers int) ScanOption {
+	return func(options *scanOptions) {
+		options.workers = workers
+	}
+}
+
+// newScanOptions returns scan options with all passed options applied.
+func newScanOptions(opts []ScanOption) *scanOptions {
+
+	options := &scanOptions{totalSegments: 1}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.totalSegments < 1 {
+		options.totalSegments = 1
+	}
+	if options.totalSegments > maxTotalSegments {
+		options.totalSegments = maxTotalSegments
+	}
+	if options.workers < 1 || int64(options.workers) > options.totalSegments {
+		options.workers = int(options.totalSegments)
+	}
+	return options
+}
+
+// Scan calls ScanWithContext with context.Background().
+func (r *DynamoDbRepository) Scan(handler ScanHandler, opts ...ScanOption) error {
+	return r.ScanWithContext(context.Background(), handler, opts...)
+}
+
+// ScanWithContext reads all items of a table, independent of their object type, and calls passed handler for each item.
+// Use WithTotalSegments and WithWorkers to scan a table in parallel. Scan stops if passed context has been canceled
+// or if a handler returns an error. In both cases this error is returned.
+func (r *DynamoDbRepository) ScanWithContext(ctx context.Context, handler ScanHandler, opts ...ScanOption) error {
+
+	options := newScanOptions(opts)
+	r.logger.Debugf("Scan table with %d segments and %d workers", options.totalSegments, options.workers)
+
+	scanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Workers take the next segment by incrementing this counter.
+	nextSegment := int64(-1)
+
+	var scanErr error
+	var errOnce sync.Once
+	stopScan := func(err error) {
+		errOnce.Do(func() {
+			scanErr = err
+			cancel()
+		})
+	}
+
+	wg := sync.WaitGroup{}
+	for worker := 0; worker < options.workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				segment := atomic.AddInt64(&nextSegment, 1)
+				if segment >= options.totalSegments || scanCtx.Err() != nil {
+					return
+				}
+				if err := r.scanSegment(scanCtx, segment, options.totalSegments, handler); err != nil {
+					stopScan(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if scanErr == nil {
+		return ctx.Err()
+	}
+	return scanErr
+}
+
+// scanSegment reads all items of passed segment and calls given handler for each of them.
+func (r *DynamoDbRepository) scanSegment(ctx context.Context, segment, totalSegments int64, handler ScanHandler) error {
+
+	input := &dynamodb.ScanInput{
+		TableName:     r.tableName,
+		Segment:       aws.Int64(segment),
+		TotalSegments: aws.Int64(totalSegments),
+	}
+
+	var handlerErr error
+	err := r.dynamoDb().ScanPagesWithContext(ctx, input, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, attributes := range page.Items {
+
+			if handlerErr = ctx.Err(); handlerErr != nil {
+				return false
+			}
+
+			key := itemKeyFromAttributes(attributes)
+			item := &ScanItem{ObjectType: key.GetObjectType(), Id: key.GetId(), attributes: attributes}
+			if handlerErr = handler(item); handlerErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if handlerErr != nil {
+		return handlerErr
+	}
+	return wrapError(err, NewItemIdentifier("", ""), ErrConditionFailed)
+}
@@ -0,0 +1,135 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/suite"
+)
+
+type ScanTestSuite struct {
+	suite.Suite
+}
+
+func TestScanTestSuite(t *testing.T) {
+	suite.Run(t, new(ScanTestSuite))
+}
+
+func (suite *ScanTestSuite) TestScanOptions() {
+
+	options := newScanOptions([]ScanOption{})
+	suite.Equal(int64(1), options.totalSegments)
+	suite.Equal(1, options.workers)
+
+	options2 := newScanOptions([]ScanOption{WithTotalSegments(8), WithWorkers(3)})
+	suite.Equal(int64(8), options2.totalSegments)
+	suite.Equal(3, options2.workers)
+
+	options3 := newScanOptions([]ScanOption{WithTotalSegments(4)})
+	suite.Equal(4, options3.workers)
+
+	options4 := newScanOptions([]ScanOption{WithTotalSegments(2), WithWorkers(5)})
+	suite.Equal(2, options4.workers)
+
+	options5 := newScanOptions([]ScanOption{WithTotalSegments(1 << 40), WithWorkers(4)})
+	suite.Equal(int64(maxTotalSegments), options5.totalSegments)
+	suite.Equal(4, options5.workers)
+}
+
+func (suite *ScanTestSuite) TestCreateClientConcurrently() {
+
+	repo := newRepositoryForTest()
+	clients := make(chan interface{}, 8)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clients <- repo.dynamoDb()
+		}()
+	}
+	wg.Wait()
+	close(clients)
+
+	for client := range clients {
+		suite.Equal(repo.dynamoDbClient, client)
+	}
+}
+
+func (suite *ScanTestSuite) TestDecodeScanItem() {
+
+	item := newItemForTest()
+	attributes, err := dynamodbattribute.MarshalMap(item)
+	suite.Nil(err)
+	scanItem := &ScanItem{ObjectType: item.GetObjectType(), Id: item.GetId(), attributes: attributes}
+	suite.Equal(item.GetId(), scanItem.GetId())
+	suite.Equal(item.GetObjectType(), scanItem.GetObjectType())
+
+	decodedItem := &testItem{}
+	suite.Nil(scanItem.Decode(decodedItem))
+	suite.Equal(item.GetId(), decodedItem.GetId())
+	suite.Equal(item.Val1, decodedItem.Val1)
+}
+
+func (suite *RepositoryTestSuite) TestScan() {
+
+	for i := 1; i <= 10; i++ {
+		suite.Nil(suite.repo.Add(newItemForTest()))
+		suite.Nil(suite.repo.Add(newVersionedItemForTest()))
+	}
+	_, err := suite.repo.Lock(newItemForTest())
+	suite.Nil(err)
+
+	lock := sync.Mutex{}
+	objectTypes := make(map[string]int)
+	handler := func(item *ScanItem) error {
+		lock.Lock()
+		defer lock.Unlock()
+		objectTypes[item.GetObjectType()]++
+		if item.GetObjectType() == "TestItems" {
+			decodedItem := &testItem{}
+			suite.Nil(item.Decode(decodedItem))
+			suite.Equal("xXx", decodedItem.Val1)
+		}
+		return nil
+	}
+	suite.Nil(suite.repo.Scan(handler, WithTotalSegments(4), WithWorkers(2)))
+	suite.Equal(10, objectTypes["TestItems"])
+	suite.Equal(10, objectTypes["TestVersionedItems"])
+	suite.Equal(1, objectTypes[lockObjectType])
+
+	stopErr := errors.New("stop")
+	handlerCalls := 0
+	err = suite.repo.Scan(func(item *ScanItem) error {
+		handlerCalls++
+		return stopErr
+	})
+	suite.Equal(stopErr, err)
+	suite.Equal(1, handlerCalls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	suite.NotNil(suite.repo.ScanWithContext(ctx, handler, WithTotalSegments(2)))
+}
+
+func (suite *RepositoryTestSuite) TestScanWithNewRepository() {
+
+	for i := 1; i <= 10; i++ {
+		suite.Nil(suite.repo.Add(newItemForTest()))
+	}
+
+	repo := NewRepository(suite.conf, loggerForTest(suite.logLevel))
+	lock := sync.Mutex{}
+	itemCount := 0
+	handler := func(item *ScanItem) error {
+		lock.Lock()
+		defer lock.Unlock()
+		itemCount++
+		return nil
+	}
+	suite.Nil(repo.Scan(handler, WithTotalSegments(8)))
+	suite.Equal(10, itemCount)
+}
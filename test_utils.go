@@ -1,8 +1,12 @@
 package dynamodb
 
 import (
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	config "github.com/tommzn/go-config"
 	log "github.com/tommzn/go-log"
 	utils "github.com/tommzn/go-utils"
@@ -17,6 +21,43 @@ func dynamoDbSettings(conf config.Config) (*string, *string, *string) {
 	return tablename, region, endpoint
 }
 
+// createIndexForTest adds a global secondary index with a string hash key and a number range key to the test table.
+func createIndexForTest(conf config.Config, index SecondaryIndex) error {
+
+	tablename, region, endpoint := dynamoDbSettings(conf)
+	sess := session.Must(session.NewSession(&aws.Config{Region: region, Endpoint: endpoint}))
+	input := &dynamodb.UpdateTableInput{
+		TableName: tablename,
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			&dynamodb.AttributeDefinition{AttributeName: aws.String(index.HashKey), AttributeType: aws.String("S")},
+			&dynamodb.AttributeDefinition{AttributeName: aws.String(index.RangeKey), AttributeType: aws.String("N")},
+		},
+		GlobalSecondaryIndexUpdates: []*dynamodb.GlobalSecondaryIndexUpdate{
+			&dynamodb.GlobalSecondaryIndexUpdate{
+				Create: &dynamodb.CreateGlobalSecondaryIndexAction{
+					IndexName: aws.String(index.Name),
+					KeySchema: []*dynamodb.KeySchemaElement{
+						&dynamodb.KeySchemaElement{AttributeName: aws.String(index.HashKey), KeyType: aws.String("HASH")},
+						&dynamodb.KeySchemaElement{AttributeName: aws.String(index.RangeKey), KeyType: aws.String("RANGE")},
+					},
+					Projection: &dynamodb.Projection{ProjectionType: aws.String("ALL")},
+					ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(1),
+						WriteCapacityUnits: aws.Int64(1),
+					},
+				},
+			},
+		},
+	}
+	_, err := dynamodb.New(sess).UpdateTable(input)
+	return err
+}
+
+// newRepositoryForTest returns a new repository created by test config.
+func newRepositoryForTest() *DynamoDbRepository {
+	return NewRepository(loadConfigForTest(), loggerForTest(log.Error)).(*DynamoDbRepository)
+}
+
 // loadConfigForTest returns test config from file testconfig.yml.
 func loadConfigForTest() config.Config {
 
@@ -44,6 +85,45 @@ func newItemForTest() *testItem {
 	}
 }
 
+// newLargeItemForTest returns a test item with a payload of passed size in bytes.
+func newLargeItemForTest(size int) *testItem {
+	item := newItemForTest()
+	item.Val1 = strings.Repeat("x", size)
+	return item
+}
+
+// testVersionedItem is a DynamoDb item with a version used for package tests.
+type testVersionedItem struct {
+	*ItemIdentifier
+	ItemVersion
+	Val1 string
+}
+
+// newVersionedItemForTest returns a versioned test item with dummy values.
+func newVersionedItemForTest() *testVersionedItem {
+	return &testVersionedItem{
+		ItemIdentifier: NewItemIdentifier(utils.NewId(), "TestVersionedItems"),
+		Val1:           "xXx",
+	}
+}
+
+// testRevisionItem is a versioned DynamoDb item which doesn't store its version in the version attribute.
+type testRevisionItem struct {
+	*ItemIdentifier
+	Rev  int64
+	Val1 string
+}
+
+// GetVersion returns the revision of a test item.
+func (item *testRevisionItem) GetVersion() int64 {
+	return item.Rev
+}
+
+// SetVersion assigns a new revision to a test item.
+func (item *testRevisionItem) SetVersion(version int64) {
+	item.Rev = version
+}
+
 // newTestItemWithoutValues copies item key from passed item into a new one.
 func newTestItemWithoutValues(item ItemKey) *testItem {
 	return &testItem{
@@ -0,0 +1,284 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// Transaction collects write actions for multiple items which will be committed atomically.
+// Either all actions succeed or none of them is applied.
+type Transaction struct {
+
+	// repository is used to commit a transaction.
+	repository *DynamoDbRepository
+
+	// keys of all items of this transaction, in same order as actions.
+	keys []ItemKey
+
+	// actions contains all collected write actions.
+	actions []*dynamodb.TransactWriteItem
+
+	// conditionFailedKinds contains the sentinel error for a failed condition of each action, in same order as actions.
+	conditionFailedKinds []error
+
+	// versionedItems have been incremented by this transaction. Their version is restored if a commit fails.
+	versionedItems []transactionVersion
+
+	// err is the first error occurred while collecting actions.
+	err error
+}
+
+// transactionVersion is a versioned item of a transaction together with its version before the transaction.
+type transactionVersion struct {
+	item            Versioned
+	previousVersion int64
+}
+
+// NewTransaction returns a new, empty transaction.
+func (r *DynamoDbRepository) NewTransaction() *Transaction {
+	return &Transaction{
+		repository:           r,
+		keys:                 []ItemKey{},
+		actions:              []*dynamodb.TransactWriteItem{},
+		conditionFailedKinds: []error{},
+		versionedItems:       []transactionVersion{},
+	}
+}
+
+// Put adds or replaces passed item. Optional conditions have to be fulfilled by an existing item.
+// A versioned item is only written if the stored item has the same version, its version is incremented.
+func (t *Transaction) Put(item ItemKey, conditions ...expression.ConditionBuilder) *Transaction {
+
+	if item.GetObjectType() == lockObjectType {
+		return t.withError(fmt.Errorf("Unsupported object type for Put: %s", item.GetObjectType()))
+	}
+
+	conditionFailedKind := ErrConditionFailed
+	if versionedItem, ok := item.(Versioned); ok {
+		conditions = append([]expression.ConditionBuilder{newVersionCondition(versionedItem.GetVersion())}, conditions...)
+		conditionFailedKind = ErrVersionConflict
+		t.incrementVersion(versionedItem)
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return t.withError(err)
+	}
+	if versionedItem, ok := item.(Versioned); ok {
+		if err := checkVersionAttribute(item, av, versionedItem.GetVersion()); err != nil {
+			return t.withError(err)
+		}
+	}
+
+	expr, err := newWriteExpression(nil, conditions)
+	if err != nil {
+		return t.withError(err)
+	}
+
+	put := &dynamodb.Put{Item: av, TableName: t.repository.tableName}
+	if expr != nil {
+		put.ConditionExpression = expr.Condition()
+		put.ExpressionAttributeNames = expr.Names()
+		put.ExpressionAttributeValues = expr.Values()
+	}
+	return t.withAction(item, &dynamodb.TransactWriteItem{Put: put}, conditionFailedKind)
+}
+
+// Delete removes an item with passed key. Optional conditions have to be fulfilled by an existing item.
+func (t *Transaction) Delete(key ItemKey, conditions ...expression.ConditionBuilder) *Transaction {
+
+	expr, err := newWriteExpression(nil, conditions)
+	if err != nil {
+		return t.withError(err)
+	}
+
+	del := &dynamodb.Delete{Key: t.repository.newItemKey(key), TableName: t.repository.tableName}
+	if expr != nil {
+		del.ConditionExpression = expr.Condition()
+		del.ExpressionAttributeNames = expr.Names()
+		del.ExpressionAttributeValues = expr.Values()
+	}
+	return t.withAction(key, &dynamodb.TransactWriteItem{Delete: del}, ErrConditionFailed)
+}
+
+// Update applies all actions of passed spec to an item with given key.
+// Conditions of passed spec have to be fulfilled by an existing item. A receiver is not supported,
+// Commit will return an error if passed spec defines one.
+// If passed key is versioned, the stored item has to have the same version, which is incremented.
+func (t *Transaction) Update(key ItemKey, spec *UpdateSpec) *Transaction {
+
+	if key.GetObjectType() == lockObjectType {
+		return t.withError(fmt.Errorf("Unsupported object type for Update: %s", key.GetObjectType()))
+	}
+	if spec.receiver != nil {
+		return t.withError(fmt.Errorf("Unsupported receiver for Update in transaction: %s", identifierAsString(key)))
+	}
+
+	conditionFailedKind := ErrConditionFailed
+	if versionedKey, ok := key.(Versioned); ok {
+		if err := validateVersionedItem(key, versionedKey); err != nil {
+			return t.withError(err)
+		}
+		spec = spec.withVersion(versionedKey.GetVersion())
+		conditionFailedKind = ErrVersionConflict
+		t.incrementVersion(versionedKey)
+	}
+
+	expr, err := spec.expression()
+	if err != nil {
+		return t.withError(err)
+	}
+
+	return t.withAction(key, &dynamodb.TransactWriteItem{Update: &dynamodb.Update{
+		Key:                       t.repository.newItemKey(key),
+		TableName:                 t.repository.tableName,
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}}, conditionFailedKind)
+}
+
+// ConditionCheck ensures an item with passed key fulfills given condition without modifying it.
+func (t *Transaction) ConditionCheck(key ItemKey, condition expression.ConditionBuilder) *Transaction {
+
+	expr, err := newWriteExpression(nil, []expression.ConditionBuilder{condition})
+	if err != nil {
+		return t.withError(err)
+	}
+
+	return t.withAction(key, &dynamodb.TransactWriteItem{ConditionCheck: &dynamodb.ConditionCheck{
+		Key:                       t.repository.newItemKey(key),
+		TableName:                 t.repository.tableName,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}}, ErrConditionFailed)
+}
+
+// Commit calls CommitWithContext with context.Background().
+func (t *Transaction) Commit() error {
+	return t.CommitWithContext(context.Background())
+}
+
+// CommitWithContext executes all collected actions in a single transaction.
+// If the transaction has been canceled by DynamoDb a TransactionError is returned.
+// Versions of versioned items are restored if a commit fails.
+func (t *Transaction) CommitWithContext(ctx context.Context) error {
+
+	if t.err != nil {
+		t.restoreVersions()
+		return t.err
+	}
+
+	t.repository.logger.Debugf("Commit transaction with %d actions", len(t.actions))
+
+	input := &dynamodb.TransactWriteItemsInput{TransactItems: t.actions}
+	_, err := t.repository.dynamoDb().TransactWriteItemsWithContext(ctx, input)
+	if err != nil {
+		t.restoreVersions()
+	}
+	return t.wrapError(err)
+}
+
+// withAction appends passed action for given item key. Passed sentinel error is used if a condition of this action fails.
+func (t *Transaction) withAction(key ItemKey, action *dynamodb.TransactWriteItem, conditionFailedKind error) *Transaction {
+	t.keys = append(t.keys, key)
+	t.actions = append(t.actions, action)
+	t.conditionFailedKinds = append(t.conditionFailedKinds, conditionFailedKind)
+	return t
+}
+
+// incrementVersion increments the version of passed item and keeps its previous version to restore it if a commit fails.
+func (t *Transaction) incrementVersion(item Versioned) {
+	t.versionedItems = append(t.versionedItems, transactionVersion{item: item, previousVersion: item.GetVersion()})
+	item.SetVersion(item.GetVersion() + 1)
+}
+
+// restoreVersions assigns the previous version to all versioned items of this transaction.
+func (t *Transaction) restoreVersions() {
+	for idx := len(t.versionedItems) - 1; idx >= 0; idx-- {
+		t.versionedItems[idx].item.SetVersion(t.versionedItems[idx].previousVersion)
+	}
+}
+
+// withError keeps passed error if there's no previous error. It will be returned by Commit.
+func (t *Transaction) withError(err error) *Transaction {
+	if t.err == nil {
+		t.err = err
+	}
+	return t
+}
+
+// wrapError converts a transaction cancellation into a TransactionError.
+// All other errors are wrapped by sentinel errors if possible.
+func (t *Transaction) wrapError(err error) error {
+
+	var canceledErr *dynamodb.TransactionCanceledException
+	if !errors.As(err, &canceledErr) {
+		return wrapError(err, NewItemIdentifier("", ""), ErrConditionFailed)
+	}
+
+	failures := []BatchItemError{}
+	for idx, reason := range canceledErr.CancellationReasons {
+
+		code := aws.StringValue(reason.Code)
+		if code == "" || code == "None" || idx >= len(t.keys) {
+			continue
+		}
+		failures = append(failures, BatchItemError{
+			Key: t.keys[idx],
+			Err: cancellationReasonError(code, aws.StringValue(reason.Message), t.keys[idx], t.conditionFailedKinds[idx]),
+		})
+	}
+	return &TransactionError{Failures: failures, Err: err}
+}
+
+// cancellationReasonError returns an error for a cancellation reason of a transaction.
+// Known reasons are reported by sentinel errors, failed conditions by passed sentinel error.
+func cancellationReasonError(code, message string, key ItemKey, conditionFailedKind error) error {
+
+	reasonErr := fmt.Errorf("%s: %s", code, message)
+	switch code {
+	case "ConditionalCheckFailed":
+		return newRepositoryError(conditionFailedKind, key, reasonErr)
+	case "TransactionConflict":
+		return newRepositoryError(ErrTransactionConflict, key, reasonErr)
+	case "ThrottlingError", "ProvisionedThroughputExceeded", "RequestLimitExceeded":
+		return newRepositoryError(ErrThrottled, key, reasonErr)
+	}
+	return reasonErr
+}
+
+// newWriteExpression builds an expression for passed update and conditions. Multiple conditions
+// are combined by AND. Returns nil if there's neither an update nor a condition.
+func newWriteExpression(update *expression.UpdateBuilder, conditions []expression.ConditionBuilder) (*expression.Expression, error) {
+
+	if update == nil && len(conditions) == 0 {
+		return nil, nil
+	}
+
+	builder := expression.NewBuilder()
+	if update != nil {
+		builder = builder.WithUpdate(*update)
+	}
+	switch len(conditions) {
+	case 0:
+	case 1:
+		builder = builder.WithCondition(conditions[0])
+	default:
+		builder = builder.WithCondition(expression.And(conditions[0], conditions[1], conditions[2:]...))
+	}
+
+	expr, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &expr, nil
+}
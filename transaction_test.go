@@ -0,0 +1,120 @@
+package dynamodb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/stretchr/testify/suite"
+)
+
+type TransactionTestSuite struct {
+	suite.Suite
+}
+
+func TestTransactionTestSuite(t *testing.T) {
+	suite.Run(t, new(TransactionTestSuite))
+}
+
+func (suite *TransactionTestSuite) TestCollectActions() {
+
+	repo := newRepositoryForTest()
+	item1 := newItemForTest()
+	item2 := newItemForTest()
+	transaction := repo.NewTransaction().
+		Put(item1, expression.AttributeNotExists(expression.Name("Id"))).
+		Delete(item2).
+		Update(item2, NewUpdateSpec().Set("Val1", "yYy")).
+		ConditionCheck(item1, expression.Name("Val2").GreaterThan(expression.Value(1)))
+	suite.Nil(transaction.err)
+	suite.Len(transaction.actions, 4)
+	suite.Equal([]ItemKey{item1, item2, item2, item1}, transaction.keys)
+	suite.NotNil(transaction.actions[0].Put.ConditionExpression)
+	suite.Nil(transaction.actions[1].Delete.ConditionExpression)
+	suite.NotNil(transaction.actions[2].Update.UpdateExpression)
+	suite.NotNil(transaction.actions[3].ConditionCheck.ConditionExpression)
+
+	itemLock := &ItemLock{ItemIdentifier: NewItemIdentifier("id-1", lockObjectType)}
+	suite.NotNil(repo.NewTransaction().Put(itemLock).Put(item1).Commit())
+
+	receiver := newItemForTest()
+	transaction2 := repo.NewTransaction().Update(item1, NewUpdateSpec().Set("Val1", "yYy").ReturnValues(receiver))
+	suite.NotNil(transaction2.err)
+	suite.Len(transaction2.actions, 0)
+	suite.NotNil(transaction2.Commit())
+}
+
+func (suite *TransactionTestSuite) TestCancellationReasons() {
+
+	repo := newRepositoryForTest()
+	item1 := newItemForTest()
+	item2 := newItemForTest()
+	transaction := repo.NewTransaction().Put(item1).Put(item2)
+
+	canceledErr := &dynamodb.TransactionCanceledException{
+		CancellationReasons: []*dynamodb.CancellationReason{
+			&dynamodb.CancellationReason{Code: aws.String("None")},
+			&dynamodb.CancellationReason{Code: aws.String("ConditionalCheckFailed"), Message: aws.String("The conditional request failed")},
+		},
+	}
+	err := transaction.wrapError(canceledErr)
+
+	var transactionErr *TransactionError
+	suite.True(errors.As(err, &transactionErr))
+	suite.Len(transactionErr.Failures, 1)
+	suite.Equal(item2, transactionErr.Failures[0].Key)
+	suite.True(errors.Is(err, ErrConditionFailed))
+	suite.False(errors.Is(err, ErrThrottled))
+
+	suite.Nil(transaction.wrapError(nil))
+}
+
+func (suite *TransactionTestSuite) TestVersionedItems() {
+
+	repo := newRepositoryForTest()
+	item := newVersionedItemForTest()
+	item.SetVersion(2)
+	item2 := newVersionedItemForTest()
+	transaction := repo.NewTransaction().
+		Put(item, expression.AttributeExists(expression.Name("Id"))).
+		Update(item2, NewUpdateSpec().Set("Val1", "yYy"))
+	suite.Nil(transaction.err)
+	suite.Equal(int64(3), item.GetVersion())
+	suite.Equal(int64(1), item2.GetVersion())
+	suite.Contains(*transaction.actions[0].Put.ConditionExpression, "AND")
+	suite.Contains(transaction.actions[0].Put.ExpressionAttributeNames, "#0")
+	suite.Equal(versionAttribute, *transaction.actions[0].Put.ExpressionAttributeNames["#0"])
+	suite.Equal("3", *transaction.actions[0].Put.Item[versionAttribute].N)
+	suite.NotNil(transaction.actions[1].Update.ConditionExpression)
+
+	canceledErr := &dynamodb.TransactionCanceledException{
+		CancellationReasons: []*dynamodb.CancellationReason{
+			&dynamodb.CancellationReason{Code: aws.String("ConditionalCheckFailed"), Message: aws.String("The conditional request failed")},
+		},
+	}
+	err := transaction.wrapError(canceledErr)
+	suite.True(errors.Is(err, ErrVersionConflict))
+	suite.False(errors.Is(err, ErrConditionFailed))
+
+	itemLock := &ItemLock{ItemIdentifier: NewItemIdentifier("id-1", lockObjectType)}
+	suite.NotNil(transaction.Put(itemLock).Commit())
+	suite.Equal(int64(2), item.GetVersion())
+	suite.Equal(int64(0), item2.GetVersion())
+}
+
+func (suite *TransactionTestSuite) TestVersionAttribute() {
+
+	repo := newRepositoryForTest()
+	item := &testRevisionItem{ItemIdentifier: NewItemIdentifier("id-1", "TestRevisionItems"), Rev: 2}
+
+	transaction := repo.NewTransaction().Put(item)
+	suite.NotNil(transaction.err)
+	suite.NotNil(transaction.Commit())
+	suite.Equal(int64(2), item.GetVersion())
+
+	transaction2 := repo.NewTransaction().Update(item, NewUpdateSpec().Set("Val1", "yYy"))
+	suite.NotNil(transaction2.err)
+	suite.Equal(int64(2), item.GetVersion())
+}
@@ -0,0 +1,86 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+)
+
+// TypedRepository provides type safe access to items of a single object type.
+// It's a wrapper for a Repository, object type of items is defined once at construction.
+type TypedRepository[T ItemKey] struct {
+
+	// repository is used to access DynamoDb.
+	repository Repository
+
+	// objectType of all items of this repository.
+	objectType string
+
+	// newItem creates an empty item for passed key, used as receiver for Get.
+	newItem func(*ItemIdentifier) T
+}
+
+// NewTypedRepository returns a new repository for items of passed object type. Given newItem func
+// have to return an empty item, usually a pointer, with passed identifier, e.g.
+// func(key *ItemIdentifier) *Order { return &Order{ItemIdentifier: key} }
+func NewTypedRepository[T ItemKey](repository Repository, objectType string, newItem func(*ItemIdentifier) T) *TypedRepository[T] {
+	return &TypedRepository[T]{
+		repository: repository,
+		objectType: objectType,
+		newItem:    newItem,
+	}
+}
+
+// Get calls GetWithContext with context.Background().
+func (r *TypedRepository[T]) Get(id string, opts ...ReadOption) (T, error) {
+	return r.GetWithContext(context.Background(), id, opts...)
+}
+
+// GetWithContext returns the item with passed id. Returns ErrNotFound if there's no such item.
+func (r *TypedRepository[T]) GetWithContext(ctx context.Context, id string, opts ...ReadOption) (T, error) {
+
+	item := r.newItem(NewItemIdentifier(id, r.objectType))
+	if err := r.repository.GetWithContext(ctx, item, opts...); err != nil {
+		var empty T
+		return empty, err
+	}
+	return item, nil
+}
+
+// Query calls QueryWithContext with context.Background().
+func (r *TypedRepository[T]) Query(opts ...ReadOption) ([]T, error) {
+	return r.QueryWithContext(context.Background(), opts...)
+}
+
+// QueryWithContext returns all items of the object type of this repository.
+func (r *TypedRepository[T]) QueryWithContext(ctx context.Context, opts ...ReadOption) ([]T, error) {
+
+	items := []T{}
+	if err := r.repository.QueryWithContext(ctx, r.objectType, &items, opts...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Add calls AddWithContext with context.Background().
+func (r *TypedRepository[T]) Add(item T) error {
+	return r.AddWithContext(context.Background(), item)
+}
+
+// AddWithContext creates or updates passed item. Object type of passed item have to match the object type of this repository.
+func (r *TypedRepository[T]) AddWithContext(ctx context.Context, item T) error {
+
+	if item.GetObjectType() != r.objectType {
+		return fmt.Errorf("Unsupported object type for %s repository: %s", r.objectType, item.GetObjectType())
+	}
+	return r.repository.AddWithContext(ctx, item)
+}
+
+// Delete calls DeleteWithContext with context.Background().
+func (r *TypedRepository[T]) Delete(id string) error {
+	return r.DeleteWithContext(context.Background(), id)
+}
+
+// DeleteWithContext removes the item with passed id.
+func (r *TypedRepository[T]) DeleteWithContext(ctx context.Context, id string) error {
+	return r.repository.DeleteWithContext(ctx, NewItemIdentifier(id, r.objectType))
+}
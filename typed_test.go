@@ -0,0 +1,37 @@
+package dynamodb
+
+import (
+	"errors"
+)
+
+func (suite *RepositoryTestSuite) TestTypedRepository() {
+
+	repo := NewTypedRepository(suite.repo, "TestItems", func(key *ItemIdentifier) *testItem {
+		return &testItem{ItemIdentifier: key}
+	})
+
+	item := newItemForTest()
+	suite.Nil(repo.Add(item))
+	suite.Nil(repo.Add(newItemForTest()))
+
+	item2, err := repo.Get(item.GetId())
+	suite.Nil(err)
+	suite.Equal(item.Val1, item2.Val1)
+	suite.Equal(item.Val2, item2.Val2)
+
+	items, err := repo.Query()
+	suite.Nil(err)
+	suite.Len(items, 2)
+	for _, queriedItem := range items {
+		suite.Equal("TestItems", queriedItem.GetObjectType())
+	}
+
+	suite.Nil(repo.Delete(item.GetId()))
+	item3, err := repo.Get(item.GetId())
+	suite.True(errors.Is(err, ErrNotFound))
+	suite.Nil(item3)
+
+	otherItem := newItemForTest()
+	otherItem.ItemIdentifier.ObjectType = "OtherItems"
+	suite.NotNil(repo.Add(otherItem))
+}
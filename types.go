@@ -1,6 +1,7 @@
 package dynamodb
 
 import (
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -14,6 +15,11 @@ type ItemIdentifier struct {
 	ObjectType string `json:"ObjectType"`
 }
 
+// ItemVersion can be used in objects which should be protected by optimistic locking.
+type ItemVersion struct {
+	Version int64 `json:"Version"`
+}
+
 // DynamoDbRepository is a wrapper to AWS DynamoDb SDK.
 type DynamoDbRepository struct {
 
@@ -29,8 +35,14 @@ type DynamoDbRepository struct {
 	// dynamoDbClient is a used to access DynamoDb apis.
 	dynamoDbClient *dynamodb.DynamoDB
 
+	// dynamoDbClientOnce ensures the DynamoDb client is created only once, even if used concurrently.
+	dynamoDbClientOnce sync.Once
+
 	// lockTtl defines the life time of a lock.
 	lockTtl time.Duration
+
+	// cursorSecret is used to sign cursors for paginated queries.
+	cursorSecret []byte
 }
 
 // QueryRequest is used to query items for a partition key.
@@ -44,6 +56,19 @@ type QueryRequest struct {
 	Items interface{}
 }
 
+// SecondaryIndex defines a global or local secondary index of a DynamoDb table.
+type SecondaryIndex struct {
+
+	// Name of an index.
+	Name string
+
+	// HashKey is the name of the partition key attribute of an index.
+	HashKey string
+
+	// RangeKey is the name of the sort key attribute of an index. Optional, can be empty.
+	RangeKey string
+}
+
 // ItemLock is a lock for an item in DynamoDb.
 type ItemLock struct {
 
@@ -0,0 +1,213 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// UpdateSpec defines actions and conditions for a partial update of an item.
+type UpdateSpec struct {
+
+	// actions are applied to an update builder to collect all update actions.
+	actions []updateAction
+
+	// conditions an item has to fulfill to be updated.
+	conditions []expression.ConditionBuilder
+
+	// receiver for item values after an update. Can be nil.
+	receiver interface{}
+}
+
+// updateAction adds a single action to an update builder.
+type updateAction func(expression.UpdateBuilder) expression.UpdateBuilder
+
+// NewUpdateSpec returns a new update spec without any actions.
+func NewUpdateSpec() *UpdateSpec {
+	return &UpdateSpec{actions: []updateAction{}, conditions: []expression.ConditionBuilder{}}
+}
+
+// Set assigns passed value to an attribute.
+func (spec *UpdateSpec) Set(name string, value interface{}) *UpdateSpec {
+	return spec.withAction(func(update expression.UpdateBuilder) expression.UpdateBuilder {
+		return update.Set(expression.Name(name), expression.Value(value))
+	})
+}
+
+// Remove deletes an attribute from an item.
+func (spec *UpdateSpec) Remove(name string) *UpdateSpec {
+	return spec.withAction(func(update expression.UpdateBuilder) expression.UpdateBuilder {
+		return update.Remove(expression.Name(name))
+	})
+}
+
+// Add adds passed value to a number attribute or passed elements to a set attribute.
+// If the attribute doesn't exist, it will be created.
+func (spec *UpdateSpec) Add(name string, value interface{}) *UpdateSpec {
+	return spec.withAction(func(update expression.UpdateBuilder) expression.UpdateBuilder {
+		return update.Add(expression.Name(name), expression.Value(value))
+	})
+}
+
+// Delete removes passed elements from a set attribute.
+func (spec *UpdateSpec) Delete(name string, value interface{}) *UpdateSpec {
+	return spec.withAction(func(update expression.UpdateBuilder) expression.UpdateBuilder {
+		return update.Delete(expression.Name(name), expression.Value(value))
+	})
+}
+
+// Condition adds a condition an item has to fulfill to be updated. Multiple conditions are combined by AND.
+func (spec *UpdateSpec) Condition(condition expression.ConditionBuilder) *UpdateSpec {
+	spec.conditions = append(spec.conditions, condition)
+	return spec
+}
+
+// ReturnValues defines a receiver all values of an item will be unmarshalled into after an update.
+// Receiver have to be a pointer.
+func (spec *UpdateSpec) ReturnValues(receiver interface{}) *UpdateSpec {
+	spec.receiver = receiver
+	return spec
+}
+
+// withAction appends passed update action.
+func (spec *UpdateSpec) withAction(action updateAction) *UpdateSpec {
+	spec.actions = append(spec.actions, action)
+	return spec
+}
+
+// withVersion returns a copy of this spec which only updates an item if it has passed version
+// and increments its version. This spec stays unchanged.
+func (spec *UpdateSpec) withVersion(previousVersion int64) *UpdateSpec {
+
+	versionSpec := &UpdateSpec{
+		actions:    append([]updateAction{}, spec.actions...),
+		conditions: append([]expression.ConditionBuilder{}, spec.conditions...),
+		receiver:   spec.receiver,
+	}
+	return versionSpec.Set(versionAttribute, previousVersion+1).Condition(newVersionCondition(previousVersion))
+}
+
+// expression builds an expression for all actions and conditions of this spec.
+func (spec *UpdateSpec) expression() (*expression.Expression, error) {
+
+	update := expression.UpdateBuilder{}
+	for _, action := range spec.actions {
+		update = action(update)
+	}
+	return newWriteExpression(&update, spec.conditions)
+}
+
+// Update calls UpdateWithContext with context.Background().
+func (r *DynamoDbRepository) Update(key ItemKey, spec *UpdateSpec) error {
+	return r.UpdateWithContext(context.Background(), key, spec)
+}
+
+// UpdateWithContext applies all actions of passed spec to an item with given key. If the item doesn't exist, it will be created.
+// Returns ErrConditionFailed if a condition of passed spec is not fulfilled. If passed key is versioned, the stored
+// item has to have the same version, which is incremented. Otherwise ErrVersionConflict is returned.
+func (r *DynamoDbRepository) UpdateWithContext(ctx context.Context, key ItemKey, spec *UpdateSpec) error {
+
+	r.logger.Debug("Update Item: ", identifierAsString(key))
+
+	if key.GetObjectType() == lockObjectType {
+		return fmt.Errorf("Unsupported object type for Update: %s", key.GetObjectType())
+	}
+
+	conditionFailedKind := ErrConditionFailed
+	versionedKey, isVersioned := key.(Versioned)
+	if isVersioned {
+		if err := validateVersionedItem(key, versionedKey); err != nil {
+			return err
+		}
+		spec = spec.withVersion(versionedKey.GetVersion())
+		conditionFailedKind = ErrVersionConflict
+	}
+
+	input, err := r.newUpdateItemInput(key, spec)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.dynamoDb().UpdateItemWithContext(ctx, input)
+	if err != nil {
+		return wrapError(err, key, conditionFailedKind)
+	}
+
+	r.logger.Debugf("Update Result: %+v", result)
+	if isVersioned {
+		versionedKey.SetVersion(versionedKey.GetVersion() + 1)
+	}
+	if spec.receiver != nil {
+		return dynamodbattribute.UnmarshalMap(result.Attributes, spec.receiver)
+	}
+	return nil
+}
+
+// Increment calls IncrementWithContext with context.Background().
+func (r *DynamoDbRepository) Increment(key ItemKey, attribute string, delta int64) (int64, error) {
+	return r.IncrementWithContext(context.Background(), key, attribute, delta)
+}
+
+// IncrementWithContext adds passed delta to a number attribute of an item with given key and returns the new value.
+// Missing attributes or items will be created, starting with a value of 0. Use a negative delta to decrement.
+// If passed key is versioned, the stored version is incremented as well, without checking it.
+// The version itself can't be incremented by passed delta.
+func (r *DynamoDbRepository) IncrementWithContext(ctx context.Context, key ItemKey, attribute string, delta int64) (int64, error) {
+
+	r.logger.Debugf("Increment %s of %s by %d", attribute, identifierAsString(key), delta)
+
+	if key.GetObjectType() == lockObjectType {
+		return 0, fmt.Errorf("Unsupported object type for Increment: %s", key.GetObjectType())
+	}
+
+	spec := NewUpdateSpec().Add(attribute, delta)
+	if versionedKey, ok := key.(Versioned); ok {
+		if attribute == versionAttribute {
+			return 0, fmt.Errorf("Unsupported attribute for Increment of versioned items: %s", attribute)
+		}
+		if err := validateVersionedItem(key, versionedKey); err != nil {
+			return 0, err
+		}
+		spec.Add(versionAttribute, 1)
+	}
+	input, err := r.newUpdateItemInput(key, spec)
+	if err != nil {
+		return 0, err
+	}
+	input.ReturnValues = aws.String(dynamodb.ReturnValueUpdatedNew)
+
+	result, err := r.dynamoDb().UpdateItemWithContext(ctx, input)
+	if err != nil {
+		return 0, wrapError(err, key, ErrConditionFailed)
+	}
+
+	var value int64
+	err = dynamodbattribute.Unmarshal(result.Attributes[attribute], &value)
+	return value, err
+}
+
+// newUpdateItemInput creates a new update item input for passed key and update spec.
+func (r *DynamoDbRepository) newUpdateItemInput(key ItemKey, spec *UpdateSpec) (*dynamodb.UpdateItemInput, error) {
+
+	expr, err := spec.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		Key:                       r.newItemKey(key),
+		TableName:                 r.tableName,
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+	if spec.receiver != nil {
+		input.ReturnValues = aws.String(dynamodb.ReturnValueAllNew)
+	}
+	return input, nil
+}
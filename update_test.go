@@ -0,0 +1,188 @@
+package dynamodb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/stretchr/testify/suite"
+)
+
+type UpdateTestSuite struct {
+	suite.Suite
+}
+
+func TestUpdateTestSuite(t *testing.T) {
+	suite.Run(t, new(UpdateTestSuite))
+}
+
+func (suite *RepositoryTestSuite) TestUpdateItem() {
+
+	item := newItemForTest()
+	suite.Nil(suite.repo.Add(item))
+
+	updatedItem := newTestItemWithoutValues(item)
+	spec := NewUpdateSpec().
+		Set("Val1", "yYy").
+		Add("Val2", 5).
+		Condition(expression.Name("Val1").Equal(expression.Value(item.Val1))).
+		ReturnValues(updatedItem)
+	suite.Nil(suite.repo.Update(item, spec))
+	suite.Equal("yYy", updatedItem.Val1)
+	suite.Equal(item.Val2+5, updatedItem.Val2)
+
+	spec2 := NewUpdateSpec().
+		Set("Val1", "zZz").
+		Condition(expression.Name("Val1").Equal(expression.Value(item.Val1)))
+	suite.True(errors.Is(suite.repo.Update(item, spec2), ErrConditionFailed))
+
+	suite.Nil(suite.repo.Update(item, NewUpdateSpec().Remove("Val1")))
+	item2 := newTestItemWithoutValues(item)
+	suite.Nil(suite.repo.Get(item2))
+	suite.Equal("", item2.Val1)
+	suite.Equal(updatedItem.Val2, item2.Val2)
+
+	suite.NotNil(suite.repo.Update(item, NewUpdateSpec()))
+
+	itemLock := &ItemLock{ItemIdentifier: NewItemIdentifier("id-1", lockObjectType)}
+	suite.NotNil(suite.repo.Update(itemLock, NewUpdateSpec().Set("ExpiresAt", 1)))
+}
+
+func (suite *RepositoryTestSuite) TestIncrement() {
+
+	item := newItemForTest()
+	suite.Nil(suite.repo.Add(item))
+
+	value, err := suite.repo.Increment(item, "Val2", 5)
+	suite.Nil(err)
+	suite.Equal(int64(item.Val2+5), value)
+
+	value, err = suite.repo.Increment(item, "Val2", -10)
+	suite.Nil(err)
+	suite.Equal(int64(item.Val2-5), value)
+
+	value, err = suite.repo.Increment(item, "Downloads", 1)
+	suite.Nil(err)
+	suite.Equal(int64(1), value)
+
+	newKey := NewItemIdentifier("counter-1", "TestCounters")
+	value, err = suite.repo.Increment(newKey, "Retries", 3)
+	suite.Nil(err)
+	suite.Equal(int64(3), value)
+
+	_, err = suite.repo.Increment(item, "Val1", 1)
+	suite.NotNil(err)
+
+	itemLock := &ItemLock{ItemIdentifier: NewItemIdentifier("id-1", lockObjectType)}
+	_, err = suite.repo.Increment(itemLock, "ExpiresAt", 1)
+	suite.NotNil(err)
+}
+
+func (suite *UpdateTestSuite) TestNewUpdateItemInput() {
+
+	repo := newRepositoryForTest()
+	receiver := newItemForTest()
+	spec := NewUpdateSpec().
+		Set("Val1", "yYy").
+		Remove("Val3").
+		Add("Val2", 1).
+		Delete("Tags", []string{"a"}).
+		Condition(expression.AttributeExists(expression.Name("Id"))).
+		Condition(expression.Name("Val2").GreaterThan(expression.Value(0))).
+		ReturnValues(receiver)
+
+	input, err := repo.newUpdateItemInput(receiver, spec)
+	suite.Nil(err)
+	suite.Contains(*input.UpdateExpression, "SET")
+	suite.Contains(*input.UpdateExpression, "REMOVE")
+	suite.Contains(*input.UpdateExpression, "ADD")
+	suite.Contains(*input.UpdateExpression, "DELETE")
+	suite.Contains(*input.ConditionExpression, "AND")
+	suite.Equal("ALL_NEW", *input.ReturnValues)
+
+	input2, err2 := repo.newUpdateItemInput(receiver, NewUpdateSpec().Set("Val1", "yYy"))
+	suite.Nil(err2)
+	suite.Nil(input2.ConditionExpression)
+	suite.Nil(input2.ReturnValues)
+
+	_, err3 := repo.newUpdateItemInput(receiver, NewUpdateSpec())
+	suite.NotNil(err3)
+}
+
+func (suite *UpdateTestSuite) TestUpdateSpecWithVersion() {
+
+	repo := newRepositoryForTest()
+	spec := NewUpdateSpec().Set("Val1", "yYy")
+	versionSpec := spec.withVersion(3)
+	suite.Len(spec.actions, 1)
+	suite.Len(spec.conditions, 0)
+	suite.Len(versionSpec.actions, 2)
+	suite.Len(versionSpec.conditions, 1)
+
+	input, err := repo.newUpdateItemInput(newVersionedItemForTest(), versionSpec)
+	suite.Nil(err)
+	suite.NotNil(input.ConditionExpression)
+	names := []string{}
+	for _, name := range input.ExpressionAttributeNames {
+		names = append(names, *name)
+	}
+	suite.Contains(names, versionAttribute)
+
+	input2, err2 := repo.newUpdateItemInput(newVersionedItemForTest(), spec)
+	suite.Nil(err2)
+	suite.Nil(input2.ConditionExpression)
+}
+
+func (suite *RepositoryTestSuite) TestUpdateVersionedItem() {
+
+	item := newVersionedItemForTest()
+	suite.Nil(suite.repo.Add(item))
+
+	item1 := &testVersionedItem{ItemIdentifier: NewItemIdentifier(item.GetId(), item.GetObjectType())}
+	suite.Nil(suite.repo.Get(item1))
+	suite.Nil(suite.repo.Update(item, NewUpdateSpec().Set("Val1", "yYy")))
+	suite.Equal(int64(2), item.GetVersion())
+
+	err := suite.repo.Update(item1, NewUpdateSpec().Set("Val1", "zZz"))
+	suite.True(errors.Is(err, ErrVersionConflict))
+	suite.Equal(int64(1), item1.GetVersion())
+
+	_, err = suite.repo.Increment(item, "Downloads", 1)
+	suite.Nil(err)
+	suite.True(errors.Is(suite.repo.Add(item), ErrVersionConflict))
+
+	suite.NotNil(suite.repo.AddAll([]ItemKey{item}))
+
+	item2 := &testVersionedItem{ItemIdentifier: NewItemIdentifier(item.GetId(), item.GetObjectType())}
+	suite.Nil(suite.repo.Get(item2))
+	suite.Equal("yYy", item2.Val1)
+	suite.Equal(int64(3), item2.GetVersion())
+
+	suite.Nil(suite.repo.NewTransaction().Put(item2).Commit())
+	suite.Equal(int64(4), item2.GetVersion())
+	suite.True(errors.Is(suite.repo.NewTransaction().Put(item1).Commit(), ErrVersionConflict))
+	suite.Equal(int64(1), item1.GetVersion())
+}
+
+func (suite *UpdateTestSuite) TestIncrementVersion() {
+
+	repo := newRepositoryForTest()
+	_, err := repo.Increment(newVersionedItemForTest(), versionAttribute, 1)
+	suite.NotNil(err)
+	suite.Contains(err.Error(), versionAttribute)
+}
+
+func (suite *UpdateTestSuite) TestVersionAttribute() {
+
+	repo := newRepositoryForTest()
+	item := &testRevisionItem{ItemIdentifier: NewItemIdentifier("id-1", "TestRevisionItems"), Rev: 2}
+
+	suite.NotNil(repo.Add(item))
+	suite.Equal(int64(2), item.GetVersion())
+	suite.NotNil(repo.Update(item, NewUpdateSpec().Set("Val1", "yYy")))
+	suite.Equal(int64(2), item.GetVersion())
+	_, err := repo.Increment(item, "Downloads", 1)
+	suite.NotNil(err)
+
+	suite.Nil(validateVersionedItem(newVersionedItemForTest(), newVersionedItemForTest()))
+}
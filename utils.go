@@ -1,8 +1,55 @@
 package dynamodb
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
 
 // identifierAsString returns a string representation of an identifier.
 func identifierAsString(id ItemKey) string {
 	return fmt.Sprintf("%s:%s", id.GetObjectType(), id.GetId())
 }
+
+// itemKeyFromAttributes extracts the item key from passed DynamoDb attributes.
+func itemKeyFromAttributes(attributes map[string]*dynamodb.AttributeValue) ItemKey {
+
+	key := &ItemIdentifier{}
+	dynamodbattribute.UnmarshalMap(attributes, key)
+	return key
+}
+
+// backoffDelay returns an exponential delay for passed retry attempt, starting with base delay for the first attempt.
+// Returns zero if the delay exceeds the max duration.
+func backoffDelay(attempt int, base time.Duration) time.Duration {
+	if attempt >= 62 || base > math.MaxInt64>>uint(attempt) {
+		return 0
+	}
+	return base * time.Duration(1<<uint(attempt))
+}
+
+// withJitter returns a random duration between half and full of passed delay.
+func withJitter(delay time.Duration) time.Duration {
+	if delay < 2 {
+		return delay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+}
+
+// sleepWithContext waits for passed duration. It returns earlier with an error if passed context is done.
+func sleepWithContext(ctx context.Context, duration time.Duration) error {
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,53 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type UtilsTestSuite struct {
+	suite.Suite
+}
+
+func TestUtilsTestSuite(t *testing.T) {
+	suite.Run(t, new(UtilsTestSuite))
+}
+
+func (suite *UtilsTestSuite) TestBackoffDelay() {
+
+	suite.Equal(50*time.Millisecond, backoffDelay(0, 50*time.Millisecond))
+	suite.Equal(100*time.Millisecond, backoffDelay(1, 50*time.Millisecond))
+	suite.Equal(400*time.Millisecond, backoffDelay(3, 50*time.Millisecond))
+	suite.Equal(time.Duration(0), backoffDelay(70, 50*time.Millisecond))
+	suite.Equal(time.Duration(0), backoffDelay(40, time.Hour))
+}
+
+func (suite *UtilsTestSuite) TestWithJitter() {
+
+	for i := 0; i < 100; i++ {
+		delay := withJitter(100 * time.Millisecond)
+		suite.True(delay >= 50*time.Millisecond)
+		suite.True(delay < 100*time.Millisecond)
+	}
+	suite.Equal(time.Duration(1), withJitter(1))
+}
+
+func (suite *UtilsTestSuite) TestSleepWithContext() {
+
+	suite.Nil(sleepWithContext(context.Background(), 1*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	suite.Equal(context.Canceled, sleepWithContext(ctx, 1*time.Hour))
+}
+
+func (suite *UtilsTestSuite) TestItemKeyFromAttributes() {
+
+	item := newItemForTest()
+	key := itemKeyFromAttributes(newRepositoryForTest().newItemKey(item))
+	suite.Equal(item.GetId(), key.GetId())
+	suite.Equal(item.GetObjectType(), key.GetObjectType())
+}